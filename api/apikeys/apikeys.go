@@ -0,0 +1,61 @@
+package apikeys
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
+)
+
+type CreateAPIKeyRequest struct {
+	OrganizationId    string   `json:"-"`
+	Name              string   `json:"name"`
+	Description       string   `json:"description,omitempty"`
+	OrganizationRoles []string `json:"organizationRoles"`
+	// Expiry is the lifetime of the key in days. Capella applies its default when zero.
+	Expiry       float64       `json:"expiry,omitempty"`
+	AllowedCIDRs []string      `json:"allowedCIDRs,omitempty"`
+	Resources    []APIKeyScope `json:"resources,omitempty"`
+}
+
+// APIKeyScope grants Roles, such as "projectDataReader" or "projectViewer", on a resource. Type is
+// "project" for a project ID.
+type APIKeyScope struct {
+	Id    string   `json:"id"`
+	Type  string   `json:"type"`
+	Roles []string `json:"roles"`
+}
+
+type CreateAPIKeyResponse struct {
+	Id    string `json:"id"`
+	Token string `json:"token"`
+}
+
+type DeleteAPIKeyRequest struct {
+	OrganizationId string
+	Id             string
+}
+
+// CreateAPIKey creates an API key and returns its ID and token. The token is only returned on creation.
+func CreateAPIKey(ctx context.Context, c *apiclient.Client, req *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error) {
+	var res *CreateAPIKeyResponse
+	path := fmt.Sprintf("v4/organizations/%s/apikeys", req.OrganizationId)
+
+	_, err := c.Post(ctx, path, req, &res)
+	if err == nil && (res == nil || res.Id == "" || res.Token == "") {
+		return nil, fmt.Errorf("create API key %q: response did not include the key ID and token", req.Name)
+	}
+	return res, err
+}
+
+// DeleteAPIKey revokes an API key.
+func DeleteAPIKey(ctx context.Context, c *apiclient.Client, req *DeleteAPIKeyRequest) error {
+	path := fmt.Sprintf("v4/organizations/%s/apikeys/%s",
+		req.OrganizationId,
+		url.PathEscape(req.Id),
+	)
+
+	_, err := c.Delete(ctx, path)
+	return err
+}
@@ -0,0 +1,88 @@
+package apikeys
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+)
+
+func newTestClient(url string) *apiclient.Client {
+	rhc := retryablehttp.NewClient()
+	rhc.RetryMax = 0
+	return apiclient.NewClient(apiclient.WithBaseURL(url), apiclient.WithHTTPClient(rhc))
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v4/organizations/org/apikeys" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if _, ok := body["organizationId"]; ok {
+			t.Errorf("body = %v, want path parameters left out", body)
+		}
+		if _, ok := body["allowedCIDRs"]; ok {
+			t.Errorf("body = %v, want unset allowedCIDRs left out", body)
+		}
+		want := `[{"id":"proj","roles":["projectDataReader"],"type":"project"}]`
+		if got, _ := json.Marshal(body["resources"]); string(got) != want {
+			t.Errorf("resources = %s, want %s", got, want)
+		}
+		if body["expiry"] != 0.5 {
+			t.Errorf("expiry = %v, want 0.5", body["expiry"])
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"key-1","token":"secret"}`))
+	}))
+	defer ts.Close()
+
+	res, err := CreateAPIKey(context.Background(), newTestClient(ts.URL), &CreateAPIKeyRequest{
+		OrganizationId:    "org",
+		Name:              "ci",
+		OrganizationRoles: []string{"organizationMember"},
+		Expiry:            0.5,
+		Resources:         []APIKeyScope{{Id: "proj", Type: "project", Roles: []string{"projectDataReader"}}},
+	})
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+	if res.Id != "key-1" || res.Token != "secret" {
+		t.Fatalf("response = %+v, want key-1 and its token", res)
+	}
+}
+
+func TestCreateAPIKey_MissingToken(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"key-1"}`))
+	}))
+	defer ts.Close()
+
+	_, err := CreateAPIKey(context.Background(), newTestClient(ts.URL), &CreateAPIKeyRequest{OrganizationId: "org", Name: "ci"})
+	if err == nil {
+		t.Fatal("CreateAPIKey() error = nil, want missing token error")
+	}
+}
+
+func TestDeleteAPIKey_NotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/v4/organizations/org/apikeys/key-1" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	err := DeleteAPIKey(context.Background(), newTestClient(ts.URL), &DeleteAPIKeyRequest{OrganizationId: "org", Id: "key-1"})
+	if !apiclient.IsNotFound(err) {
+		t.Fatalf("DeleteAPIKey() error = %v, want not found", err)
+	}
+}
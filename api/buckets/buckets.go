@@ -0,0 +1,103 @@
+package buckets
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
+)
+
+// listPageSize is the number of buckets requested per page by ListBuckets.
+const listPageSize = 100
+
+type ListBucketsRequest struct {
+	OrganizationId string
+	ProjectId      string
+	ClusterId      string
+}
+
+// Bucket is an entry in the bucket list of a cluster. ID is the identifier used in bucket
+// paths; it differs from Name.
+type Bucket struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type listBucketsPage struct {
+	Data   []Bucket `json:"data"`
+	Cursor *struct {
+		Pages struct {
+			Next int `json:"next"`
+		} `json:"pages"`
+	} `json:"cursor,omitempty"`
+}
+
+type ListScopesRequest struct {
+	OrganizationId string
+	ProjectId      string
+	ClusterId      string
+	BucketId       string
+}
+
+// Scope is a scope of a bucket together with its collections.
+type Scope struct {
+	Name        string       `json:"name"`
+	Collections []Collection `json:"collections"`
+}
+
+type Collection struct {
+	Name string `json:"name"`
+}
+
+type ListScopesResponse struct {
+	Scopes []Scope `json:"scopes"`
+}
+
+// ListBuckets returns every bucket in a cluster, following the pages of the list.
+func ListBuckets(ctx context.Context, c *apiclient.Client, req *ListBucketsRequest) ([]Bucket, error) {
+	path := fmt.Sprintf("v4/organizations/%s/projects/%s/clusters/%s/buckets",
+		req.OrganizationId,
+		req.ProjectId,
+		req.ClusterId,
+	)
+
+	var buckets []Bucket
+	for page := 1; ; {
+		var res *listBucketsPage
+		_, err := c.GetLenient(ctx, path, map[string]string{
+			"page":    strconv.Itoa(page),
+			"perPage": strconv.Itoa(listPageSize),
+		}, &res)
+		if err != nil {
+			return buckets, err
+		}
+		if res == nil {
+			return buckets, nil
+		}
+		buckets = append(buckets, res.Data...)
+		// The last page reports no next page; guard against a cursor that does not advance.
+		if res.Cursor == nil || res.Cursor.Pages.Next <= page {
+			return buckets, nil
+		}
+		page = res.Cursor.Pages.Next
+	}
+}
+
+// ListScopes returns the scopes of a bucket and the collections in each.
+func ListScopes(ctx context.Context, c *apiclient.Client, req *ListScopesRequest) (*ListScopesResponse, error) {
+	var res *ListScopesResponse
+	path := fmt.Sprintf("v4/organizations/%s/projects/%s/clusters/%s/buckets/%s/scopes",
+		req.OrganizationId,
+		req.ProjectId,
+		req.ClusterId,
+		url.PathEscape(req.BucketId),
+	)
+
+	_, err := c.GetLenient(ctx, path, nil, &res)
+	if err == nil && res == nil {
+		res = &ListScopesResponse{}
+	}
+	return res, err
+}
@@ -0,0 +1,75 @@
+package buckets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+)
+
+func newTestClient(url string) *apiclient.Client {
+	rhc := retryablehttp.NewClient()
+	rhc.RetryMax = 0
+	return apiclient.NewClient(apiclient.WithBaseURL(url), apiclient.WithHTTPClient(rhc))
+}
+
+func TestListBuckets_Pages(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v4/organizations/org/projects/proj/clusters/cluster/buckets" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch page := r.URL.Query().Get("page"); page {
+		case "1":
+			_, _ = w.Write([]byte(`{"data":[{"id":"YQ==","name":"a"}],"cursor":{"pages":{"page":1,"next":2}}}`))
+		case "2":
+			_, _ = w.Write([]byte(`{"data":[{"id":"Yg==","name":"b"}],"cursor":{"pages":{"page":2,"next":0}}}`))
+		default:
+			t.Errorf("unexpected page %q", page)
+		}
+	}))
+	defer ts.Close()
+
+	buckets, err := ListBuckets(context.Background(), newTestClient(ts.URL), &ListBucketsRequest{
+		OrganizationId: "org",
+		ProjectId:      "proj",
+		ClusterId:      "cluster",
+	})
+	if err != nil {
+		t.Fatalf("ListBuckets() error = %v", err)
+	}
+	if got := fmt.Sprint(buckets); got != "[{YQ== a} {Yg== b}]" {
+		t.Fatalf("buckets = %s, want a and b", got)
+	}
+}
+
+func TestListScopes(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v4/organizations/org/projects/proj/clusters/cluster/buckets/YQ==/scopes" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ListScopesResponse{Scopes: []Scope{
+			{Name: "inventory", Collections: []Collection{{Name: "route"}, {Name: "airline"}}},
+		}})
+	}))
+	defer ts.Close()
+
+	res, err := ListScopes(context.Background(), newTestClient(ts.URL), &ListScopesRequest{
+		OrganizationId: "org",
+		ProjectId:      "proj",
+		ClusterId:      "cluster",
+		BucketId:       "YQ==",
+	})
+	if err != nil {
+		t.Fatalf("ListScopes() error = %v", err)
+	}
+	if len(res.Scopes) != 1 || len(res.Scopes[0].Collections) != 2 {
+		t.Fatalf("scopes = %+v, want inventory with two collections", res.Scopes)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -77,6 +78,8 @@ type Client struct {
 	HTTP      *retryablehttp.Client
 	Auth      Authenticator
 	UserAgent string
+	// Limiter, if set, throttles every request (including retries) sent by this client.
+	Limiter *RateLimiter
 	// Optional: an organization or project can be tracked by the provider side if needed
 	OrganizationID string
 	ProjectID      string
@@ -100,9 +103,15 @@ func WithBaseURL(raw string) Option {
 	}
 }
 
-// WithHTTPClient allows providing a custom retryablehttp.Client.
+// WithHTTPClient allows providing a custom retryablehttp.Client. Its retry policy, backoff,
+// error handler and retry preparation are replaced with the client's own, so retries keep
+// following RetryPolicy, RetryBackoff and the rate limiter; its retry counts, waits, transport
+// and logger are kept.
 func WithHTTPClient(rhc *retryablehttp.Client) Option {
-	return func(c *Client) { c.HTTP = rhc }
+	return func(c *Client) {
+		c.HTTP = rhc
+		c.installRetryHooks()
+	}
 }
 
 // WithAuthenticator sets the request authenticator.
@@ -115,6 +124,26 @@ func WithUserAgent(ua string) Option {
 	return func(c *Client) { c.UserAgent = ua }
 }
 
+// WithRetryMax sets the maximum number of retries for a single request. Zero disables retries.
+func WithRetryMax(n int) Option {
+	return func(c *Client) { c.HTTP.RetryMax = n }
+}
+
+// WithRetryWait sets the minimum and maximum wait between retries. Server-requested
+// waits (Retry-After and rate-limit reset headers) are honoured regardless of waitMax, up to
+// MaxServerRetryWait.
+func WithRetryWait(waitMin, waitMax time.Duration) Option {
+	return func(c *Client) {
+		c.HTTP.RetryWaitMin = waitMin
+		c.HTTP.RetryWaitMax = waitMax
+	}
+}
+
+// WithRateLimiter throttles requests through l. Passing nil disables client-side throttling.
+func WithRateLimiter(l *RateLimiter) Option {
+	return func(c *Client) { c.Limiter = l }
+}
+
 // WithOrgID sets a default organization ID on the client (optional convenience).
 func WithOrgID(id string) Option { return func(c *Client) { c.OrganizationID = id } }
 
@@ -122,15 +151,14 @@ func WithOrgID(id string) Option { return func(c *Client) { c.OrganizationID = i
 func WithProjectID(id string) Option { return func(c *Client) { c.ProjectID = id } }
 
 // NewClient creates a new Capella v4 API client.
-// The client uses retryablehttp with sensible defaults for Terraform providers.
+// The client uses retryablehttp with RetryPolicy and RetryBackoff, which respect Capella's rate limits.
 func NewClient(opts ...Option) *Client {
 	base, _ := url.Parse(DefaultBaseURL)
 
 	rhc := retryablehttp.NewClient()
-	rhc.RetryMax = 4
-	rhc.RetryWaitMin = 500 * time.Millisecond
-	rhc.RetryWaitMax = 4 * time.Second
-	rhc.Backoff = retryablehttp.DefaultBackoff
+	rhc.RetryMax = DefaultRetryMax
+	rhc.RetryWaitMin = DefaultRetryWaitMin
+	rhc.RetryWaitMax = DefaultRetryWaitMax
 	rhc.Logger = nil // do not spam logs; provider can log around the client
 
 	c := &Client{
@@ -138,30 +166,120 @@ func NewClient(opts ...Option) *Client {
 		HTTP:      rhc,
 		UserAgent: "capellaextras-terraform-provider/unknown (+https://github.com/cdsre/terraform-provider-capellaextras)",
 	}
+	c.installRetryHooks()
 	for _, o := range opts {
 		o(c)
 	}
 	return c
 }
 
-// apiError models a common API error payload. Capella uses standard patterns.
-type apiError struct {
-	Code    string `json:"code,omitempty"`
+// installRetryHooks makes c.HTTP retry with RetryPolicy and RetryBackoff through the rate limiter.
+func (c *Client) installRetryHooks() {
+	c.HTTP.CheckRetry = RetryPolicy
+	c.HTTP.Backoff = RetryBackoff
+	// Return the last response once retries are exhausted so Do can surface it as an *APIError.
+	c.HTTP.ErrorHandler = retryablehttp.PassthroughErrorHandler
+	// Retries count against the rate limit too; the first attempt is throttled in Do.
+	c.HTTP.PrepareRetry = func(req *http.Request) error {
+		return c.Limiter.Wait(req.Context())
+	}
+}
+
+// requestIDHeaders are the response headers checked, in order, for a request ID to report in errors.
+var requestIDHeaders = []string{"X-Request-Id", "Request-Id", "X-Correlation-Id"}
+
+// APIError is returned by Do for any non-2xx response. Use errors.As to inspect it,
+// or the IsNotFound/IsConflict/IsRateLimited helpers for the common cases.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Code is the Capella error code from the response body, if any.
+	Code string
+	// Message is the human readable error message from the response body, if any.
+	Message string
+	// Hint is Capella's suggested remediation from the response body, if any.
+	Hint string
+	// Detail holds any additional error detail from the response body.
+	Detail any
+	// RequestID is the request ID reported by the API, useful when raising support tickets.
+	RequestID string
+	// Method and Path identify the request that failed.
+	Method string
+	Path   string
+	// Body is the raw response body, kept when it could not be decoded as an error payload.
+	Body string
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "capella api %s %s failed: status %d", e.Method, e.Path, e.StatusCode)
+	switch {
+	case e.Code != "" && e.Message != "":
+		fmt.Fprintf(&b, ": %s: %s", e.Code, e.Message)
+	case e.Message != "":
+		fmt.Fprintf(&b, ": %s", e.Message)
+	case e.Code != "":
+		fmt.Fprintf(&b, ": %s", e.Code)
+	case e.Body != "":
+		fmt.Fprintf(&b, ", body: %s", e.Body)
+	}
+	if e.Hint != "" {
+		fmt.Fprintf(&b, " (hint: %s)", e.Hint)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " [request id: %s]", e.RequestID)
+	}
+	return b.String()
+}
+
+// errorPayload models the common Capella error body. Capella reports code as a number while
+// other services use strings, so it is decoded loosely.
+type errorPayload struct {
+	Code    any    `json:"code,omitempty"`
 	Message string `json:"message,omitempty"`
+	Hint    string `json:"hint,omitempty"`
 	Detail  any    `json:"detail,omitempty"`
 }
 
-func (e apiError) Error() string {
-	if e.Code == "" && e.Message == "" {
-		return "capella api error"
+func newAPIError(req *http.Request, resp *http.Response, body []byte) *APIError {
+	ae := &APIError{
+		StatusCode: resp.StatusCode,
+		Method:     req.Method,
+		Path:       req.URL.Path,
 	}
-	if e.Code == "" {
-		return e.Message
+	for _, h := range requestIDHeaders {
+		if id := resp.Header.Get(h); id != "" {
+			ae.RequestID = id
+			break
+		}
 	}
-	if e.Message == "" {
-		return e.Code
+
+	var payload errorPayload
+	if json.Unmarshal(body, &payload) == nil && (payload.Code != nil || payload.Message != "") {
+		if payload.Code != nil {
+			ae.Code = fmt.Sprint(payload.Code)
+		}
+		ae.Message = payload.Message
+		ae.Hint = payload.Hint
+		ae.Detail = payload.Detail
+		return ae
 	}
-	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+	ae.Body = string(body)
+	return ae
+}
+
+// IsNotFound reports whether err is an APIError with HTTP status 404.
+func IsNotFound(err error) bool { return hasStatus(err, http.StatusNotFound) }
+
+// IsConflict reports whether err is an APIError with HTTP status 409.
+func IsConflict(err error) bool { return hasStatus(err, http.StatusConflict) }
+
+// IsRateLimited reports whether err is an APIError with HTTP status 429.
+func IsRateLimited(err error) bool { return hasStatus(err, http.StatusTooManyRequests) }
+
+func hasStatus(err error, status int) bool {
+	var ae *APIError
+	return errors.As(err, &ae) && ae.StatusCode == status
 }
 
 // Do performs an HTTP request against the Capella API. Path may be absolute or relative.
@@ -207,7 +325,7 @@ func (c *Client) Do(ctx context.Context, method, p string, query map[string]stri
 	if err != nil {
 		return nil, err
 	}
-	req = req.WithContext(ctx)
+	req = req.WithContext(withMethod(ctx, method))
 
 	// Set headers
 	if body != nil {
@@ -225,6 +343,16 @@ func (c *Client) Do(ctx context.Context, method, p string, query map[string]stri
 		}
 	}
 
+	// Throttle before executing; the concurrency slot is held across retries.
+	release, err := c.Limiter.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if err := c.Limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
 	// Execute
 	resp, err := c.HTTP.Do(req)
 	if err != nil {
@@ -243,11 +371,7 @@ func (c *Client) Do(ctx context.Context, method, p string, query map[string]stri
 		// try to decode error
 		b, _ := io.ReadAll(resp.Body)
 		_ = resp.Body.Close()
-		var ae apiError
-		if json.Unmarshal(b, &ae) == nil && (ae.Code != "" || ae.Message != "") {
-			return resp, fmt.Errorf("%w (status %d)", ae, resp.StatusCode)
-		}
-		return resp, fmt.Errorf("capella api request failed: status %d, body: %s", resp.StatusCode, string(b))
+		return resp, newAPIError(req.Request, resp, b)
 	}
 
 	if out != nil {
@@ -270,6 +394,17 @@ func (c *Client) Get(ctx context.Context, p string, query map[string]string, out
 	return c.Do(ctx, http.MethodGet, p, query, nil, out)
 }
 
+// GetLenient is Get ignoring response fields out does not declare, for responses that carry more
+// than is modelled, which Do's strict decoding rejects.
+func (c *Client) GetLenient(ctx context.Context, p string, query map[string]string, out any) (*http.Response, error) {
+	var raw json.RawMessage
+	resp, err := c.Get(ctx, p, query, &raw)
+	if err != nil || len(raw) == 0 {
+		return resp, err
+	}
+	return resp, json.Unmarshal(raw, out)
+}
+
 func (c *Client) Post(ctx context.Context, p string, body any, out any) (*http.Response, error) {
 	return c.Do(ctx, http.MethodPost, p, nil, body, out)
 }
@@ -2,8 +2,11 @@ package client
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	retryablehttp "github.com/hashicorp/go-retryablehttp"
@@ -105,3 +108,97 @@ func TestClient_Do_BearerAuthHeaderMissing(t *testing.T) {
 		t.Fatalf("expected status 401, got resp=%v", resp)
 	}
 }
+
+// Test that a non-2xx JSON error response is returned as an *APIError with all fields populated.
+func TestClient_Do_APIErrorDecoded(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"code":4025,"message":"index not found","hint":"check the index name"}`))
+	}))
+	defer ts.Close()
+
+	rhc := retryablehttp.NewClient()
+	rhc.RetryMax = 0
+	c := NewClient(WithBaseURL(ts.URL), WithHTTPClient(rhc))
+
+	var out any
+	_, err := c.Get(context.Background(), "/v4/things/idx1", nil, &out)
+
+	var ae *APIError
+	if !errors.As(err, &ae) {
+		t.Fatalf("expected *APIError, got %T: %v", err, err)
+	}
+	if ae.StatusCode != http.StatusNotFound || ae.Code != "4025" || ae.Message != "index not found" ||
+		ae.Hint != "check the index name" || ae.RequestID != "req-123" ||
+		ae.Method != http.MethodGet || ae.Path != "/v4/things/idx1" {
+		t.Fatalf("unexpected APIError: %+v", ae)
+	}
+	if !IsNotFound(err) || IsConflict(err) || IsRateLimited(err) {
+		t.Fatalf("status helpers disagree with status %d", ae.StatusCode)
+	}
+	if got := err.Error(); !strings.Contains(got, "status 404") || !strings.Contains(got, "req-123") {
+		t.Fatalf("Error() = %q, want status and request id", got)
+	}
+}
+
+// Test that a non-JSON error body is preserved on the *APIError.
+func TestClient_Do_APIErrorRawBody(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`upstream busy`))
+	}))
+	defer ts.Close()
+
+	rhc := retryablehttp.NewClient()
+	rhc.RetryMax = 0
+	c := NewClient(WithBaseURL(ts.URL), WithHTTPClient(rhc))
+
+	_, err := c.Post(context.Background(), "/v4/things", map[string]string{"a": "b"}, nil)
+
+	var ae *APIError
+	if !errors.As(err, &ae) {
+		t.Fatalf("expected *APIError, got %T: %v", err, err)
+	}
+	if ae.Body != "upstream busy" || ae.Code != "" || !IsConflict(err) {
+		t.Fatalf("unexpected APIError: %+v", ae)
+	}
+}
+
+// Test that GetLenient ignores fields the output does not declare, which Get rejects.
+func TestClient_GetLenient_UnknownFields(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"idx1","extra":true}`))
+	}))
+	defer ts.Close()
+
+	rhc := retryablehttp.NewClient()
+	rhc.RetryMax = 0
+	c := NewClient(WithBaseURL(ts.URL), WithHTTPClient(rhc))
+
+	var out struct {
+		Name string `json:"name"`
+	}
+	if _, err := c.Get(context.Background(), "/v4/things/idx1", nil, &out); err == nil {
+		t.Fatal("Get() error = nil, want unknown field error")
+	}
+	if _, err := c.GetLenient(context.Background(), "/v4/things/idx1", nil, &out); err != nil || out.Name != "idx1" {
+		t.Fatalf("GetLenient() = (%+v, %v), want name idx1", out, err)
+	}
+}
+
+// Test that the status helpers see through wrapped errors and ignore unrelated ones.
+func TestIsNotFound_Wrapped(t *testing.T) {
+	err := fmt.Errorf("cannot get index: %w", &APIError{StatusCode: http.StatusNotFound})
+	if !IsNotFound(err) {
+		t.Fatalf("IsNotFound(%v) = false, want true", err)
+	}
+	if IsNotFound(errors.New("status 404")) {
+		t.Fatalf("IsNotFound matched a plain error")
+	}
+	if !IsRateLimited(&APIError{StatusCode: http.StatusTooManyRequests}) {
+		t.Fatalf("IsRateLimited did not match status 429")
+	}
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter bounds how fast and how many requests a Client sends at once. It is shared by
+// every resource using the Client, so a workspace stays under Capella's organisation-wide
+// API quota no matter how many resources Terraform refreshes in parallel.
+type RateLimiter struct {
+	mu       sync.Mutex
+	rate     float64 // tokens added per second; zero disables rate limiting
+	burst    float64
+	tokens   float64
+	last     time.Time
+	inflight chan struct{} // nil disables the concurrency limit
+
+	now func() time.Time
+}
+
+// NewRateLimiter returns a token-bucket limiter allowing requestsPerSecond requests per second
+// with at most maxConcurrent requests in flight. A zero value disables the respective limit.
+// The bucket holds up to one second's worth of requests so short bursts are not penalised.
+func NewRateLimiter(requestsPerSecond float64, maxConcurrent int) *RateLimiter {
+	l := &RateLimiter{
+		rate: requestsPerSecond,
+		now:  time.Now,
+	}
+	if requestsPerSecond > 0 {
+		l.burst = math.Max(1, math.Ceil(requestsPerSecond))
+		l.tokens = l.burst
+		l.last = l.now()
+	}
+	if maxConcurrent > 0 {
+		l.inflight = make(chan struct{}, maxConcurrent)
+	}
+	return l
+}
+
+// Acquire blocks until a concurrency slot is free. The returned release func must be called
+// once the request, including any retries, has finished.
+func (l *RateLimiter) Acquire(ctx context.Context) (release func(), err error) {
+	if l == nil || l.inflight == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.inflight <- struct{}{}:
+		return func() { <-l.inflight }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Wait blocks until a request token is available.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil || l.rate <= 0 {
+		return nil
+	}
+	for {
+		delay := l.reserve()
+		if delay <= 0 {
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// reserve takes a token if one is available, otherwise returns how long until one will be.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+)
+
+// Retry defaults used by NewClient. They can be overridden with WithRetryMax and WithRetryWait.
+const (
+	DefaultRetryMax     = 4
+	DefaultRetryWaitMin = 500 * time.Millisecond
+	DefaultRetryWaitMax = 30 * time.Second
+)
+
+// MaxServerRetryWait caps a wait requested by the API through Retry-After or a rate-limit reset
+// header, so a bogus or very distant reset time cannot block a request indefinitely.
+const MaxServerRetryWait = 5 * time.Minute
+
+// rateLimitResetHeaders are checked, in order, when a 429 response has no Retry-After header.
+// Values may be either a number of seconds or a Unix timestamp.
+var rateLimitResetHeaders = []string{"X-RateLimit-Reset", "RateLimit-Reset"}
+
+type methodContextKey struct{}
+
+// withMethod records the request method on ctx so RetryPolicy can see it when the
+// request fails before a response is received.
+func withMethod(ctx context.Context, method string) context.Context {
+	return context.WithValue(ctx, methodContextKey{}, method)
+}
+
+// isIdempotent reports whether a request with the given method can be safely repeated.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// RetryPolicy is a retryablehttp.CheckRetry tuned for the Capella API:
+//
+//   - 429 responses are always retried: the request was rejected, not processed.
+//   - 5xx responses and transport errors are only retried for idempotent methods, so
+//     statements such as BUILD INDEX submitted via POST are never replayed blindly.
+//   - Everything else, including other 4xx responses, is returned to the caller.
+func RetryPolicy(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	method, _ := ctx.Value(methodContextKey{}).(string)
+	if resp != nil && resp.Request != nil {
+		method = resp.Request.Method
+	}
+
+	if err != nil {
+		if !isIdempotent(method) {
+			return false, nil
+		}
+		// Defer to retryablehttp for transport errors that are never worth retrying
+		// (bad TLS certificates, redirect loops, unsupported schemes).
+		return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true, nil
+	case resp.StatusCode == http.StatusNotImplemented:
+		return false, nil
+	case resp.StatusCode >= 500:
+		return isIdempotent(method), nil
+	}
+	return false, nil
+}
+
+// RetryBackoff is a retryablehttp.Backoff that waits as long as the API asks via Retry-After
+// or a rate-limit reset header, up to MaxServerRetryWait, and otherwise falls back to exponential
+// backoff between waitMin and waitMax.
+func RetryBackoff(waitMin, waitMax time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if wait, ok := retryAfter(resp.Header, time.Now()); ok {
+			return min(wait, MaxServerRetryWait)
+		}
+	}
+	return retryablehttp.DefaultBackoff(waitMin, waitMax, attemptNum, nil)
+}
+
+// retryAfter extracts the server-requested wait from Retry-After or a rate-limit reset header.
+func retryAfter(h http.Header, now time.Time) (time.Duration, bool) {
+	if v := h.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second, true
+		}
+		if at, err := http.ParseTime(v); err == nil {
+			return max(at.Sub(now), 0), true
+		}
+	}
+	for _, name := range rateLimitResetHeaders {
+		v := h.Get(name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			continue
+		}
+		// Anything larger than a day is treated as a Unix timestamp rather than a delta.
+		if n > int64(24*time.Hour/time.Second) {
+			return max(time.Unix(n, 0).Sub(now), 0), true
+		}
+		return time.Duration(n) * time.Second, true
+	}
+	return 0, false
+}
@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+)
+
+// flakyServer responds with failStatus for the first failures requests and 200 afterwards.
+func flakyServer(t *testing.T, failStatus, failures int, header http.Header) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		if int(n) <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(failStatus)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	return ts, &calls
+}
+
+func newRetryTestClient(url string) *Client {
+	return NewClient(
+		WithBaseURL(url),
+		WithRetryMax(2),
+		WithRetryWait(time.Millisecond, 5*time.Millisecond),
+	)
+}
+
+// Test that idempotent requests are retried on 5xx.
+func TestRetryPolicy_GetRetriedOnServerError(t *testing.T) {
+	ts, calls := flakyServer(t, http.StatusServiceUnavailable, 1, nil)
+	defer ts.Close()
+
+	if _, err := newRetryTestClient(ts.URL).Get(context.Background(), "/x", nil, nil); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Fatalf("calls = %d, want 2", got)
+	}
+}
+
+// Test that POST is not replayed on 5xx, and the final response surfaces as an *APIError.
+func TestRetryPolicy_PostNotRetriedOnServerError(t *testing.T) {
+	ts, calls := flakyServer(t, http.StatusInternalServerError, 1, nil)
+	defer ts.Close()
+
+	_, err := newRetryTestClient(ts.URL).Post(context.Background(), "/x", map[string]string{}, nil)
+	if !hasStatus(err, http.StatusInternalServerError) {
+		t.Fatalf("Post() error = %v, want *APIError with status 500", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Fatalf("calls = %d, want 1", got)
+	}
+}
+
+// Test that rate-limited requests are retried regardless of method.
+func TestRetryPolicy_PostRetriedOnRateLimit(t *testing.T) {
+	ts, calls := flakyServer(t, http.StatusTooManyRequests, 1, http.Header{"Retry-After": {"0"}})
+	defer ts.Close()
+
+	if _, err := newRetryTestClient(ts.URL).Post(context.Background(), "/x", map[string]string{}, nil); err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Fatalf("calls = %d, want 2", got)
+	}
+}
+
+// Test that exhausting retries returns the last response as a rate-limited *APIError.
+func TestRetryPolicy_ExhaustedReturnsAPIError(t *testing.T) {
+	ts, calls := flakyServer(t, http.StatusTooManyRequests, 10, http.Header{"Retry-After": {"0"}})
+	defer ts.Close()
+
+	_, err := newRetryTestClient(ts.URL).Get(context.Background(), "/x", nil, nil)
+	if !IsRateLimited(err) {
+		t.Fatalf("Get() error = %v, want rate limited *APIError", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 3 {
+		t.Fatalf("calls = %d, want 3", got)
+	}
+}
+
+// Test that a custom HTTP client still retries through RetryPolicy, so POST is not replayed on 5xx.
+func TestWithHTTPClient_KeepsRetryPolicy(t *testing.T) {
+	ts, calls := flakyServer(t, http.StatusInternalServerError, 1, nil)
+	defer ts.Close()
+
+	rhc := retryablehttp.NewClient()
+	rhc.RetryWaitMin, rhc.RetryWaitMax = time.Millisecond, 5*time.Millisecond
+	_, err := NewClient(WithBaseURL(ts.URL), WithHTTPClient(rhc)).Post(context.Background(), "/x", map[string]string{}, nil)
+	if !hasStatus(err, http.StatusInternalServerError) {
+		t.Fatalf("Post() error = %v, want *APIError with status 500", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Fatalf("calls = %d, want 1", got)
+	}
+}
+
+func TestRetryBackoff_CapsServerWait(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"86400"}}}
+	if got := RetryBackoff(time.Millisecond, time.Second, 1, resp); got != MaxServerRetryWait {
+		t.Fatalf("RetryBackoff() = %s, want %s", got, MaxServerRetryWait)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+		wantOK bool
+	}{
+		{"none", http.Header{}, 0, false},
+		{"retry-after seconds", http.Header{"Retry-After": {"7"}}, 7 * time.Second, true},
+		{"retry-after date", http.Header{"Retry-After": {now.Add(90 * time.Second).Format(http.TimeFormat)}}, 90 * time.Second, true},
+		{"retry-after past date", http.Header{"Retry-After": {now.Add(-time.Minute).Format(http.TimeFormat)}}, 0, true},
+		{"reset delta", http.Header{"X-Ratelimit-Reset": {"12"}}, 12 * time.Second, true},
+		{"reset timestamp", http.Header{"X-Ratelimit-Reset": {"1735732830"}}, 30 * time.Second, true},
+		{"garbage", http.Header{"Retry-After": {"soon"}, "X-Ratelimit-Reset": {"later"}}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := retryAfter(tt.header, now)
+			if got != tt.want || ok != tt.wantOK {
+				t.Fatalf("retryAfter() = (%s, %v), want (%s, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
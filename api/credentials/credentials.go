@@ -0,0 +1,84 @@
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
+)
+
+type CreateDatabaseCredentialRequest struct {
+	OrganizationId string `json:"-"`
+	ProjectId      string `json:"-"`
+	ClusterId      string `json:"-"`
+	Name           string `json:"name"`
+	// Password is generated by Capella when empty.
+	Password string   `json:"password,omitempty"`
+	Access   []Access `json:"access"`
+}
+
+// Access grants privileges, such as "data_reader" or "data_writer", on Resources, or on every
+// bucket of the cluster when Resources is nil.
+type Access struct {
+	Privileges []string         `json:"privileges"`
+	Resources  *AccessResources `json:"resources,omitempty"`
+}
+
+type AccessResources struct {
+	Buckets []BucketAccess `json:"buckets"`
+}
+
+// BucketAccess limits an Access to a bucket, and to Scopes of it when set.
+type BucketAccess struct {
+	Name   string        `json:"name"`
+	Scopes []ScopeAccess `json:"scopes,omitempty"`
+}
+
+// ScopeAccess limits an Access to a scope, and to Collections of it when set.
+type ScopeAccess struct {
+	Name        string   `json:"name"`
+	Collections []string `json:"collections,omitempty"`
+}
+
+type CreateDatabaseCredentialResponse struct {
+	Id string `json:"id"`
+	// Password is the generated password when the request did not set one.
+	Password string `json:"password,omitempty"`
+}
+
+type DeleteDatabaseCredentialRequest struct {
+	OrganizationId string
+	ProjectId      string
+	ClusterId      string
+	Id             string
+}
+
+// CreateDatabaseCredential creates a database user on a cluster and returns its ID and password.
+func CreateDatabaseCredential(ctx context.Context, c *apiclient.Client, req *CreateDatabaseCredentialRequest) (*CreateDatabaseCredentialResponse, error) {
+	var res *CreateDatabaseCredentialResponse
+	path := fmt.Sprintf("v4/organizations/%s/projects/%s/clusters/%s/users",
+		req.OrganizationId,
+		req.ProjectId,
+		req.ClusterId,
+	)
+
+	_, err := c.Post(ctx, path, req, &res)
+	if err == nil && (res == nil || res.Id == "") {
+		return nil, fmt.Errorf("create database credential %q: response did not include the credential ID", req.Name)
+	}
+	return res, err
+}
+
+// DeleteDatabaseCredential deletes a database user.
+func DeleteDatabaseCredential(ctx context.Context, c *apiclient.Client, req *DeleteDatabaseCredentialRequest) error {
+	path := fmt.Sprintf("v4/organizations/%s/projects/%s/clusters/%s/users/%s",
+		req.OrganizationId,
+		req.ProjectId,
+		req.ClusterId,
+		url.PathEscape(req.Id),
+	)
+
+	_, err := c.Delete(ctx, path)
+	return err
+}
@@ -0,0 +1,80 @@
+package credentials
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+)
+
+func newTestClient(url string) *apiclient.Client {
+	rhc := retryablehttp.NewClient()
+	rhc.RetryMax = 0
+	return apiclient.NewClient(apiclient.WithBaseURL(url), apiclient.WithHTTPClient(rhc))
+}
+
+func TestCreateDatabaseCredential(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v4/organizations/org/projects/proj/clusters/cluster/users" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if _, ok := body["organizationId"]; ok {
+			t.Errorf("body = %v, want path parameters left out", body)
+		}
+		want := `[{"privileges":["data_reader"],"resources":{"buckets":[{"name":"b","scopes":[{"collections":["c"],"name":"s"}]}]}}]`
+		if got, _ := json.Marshal(body["access"]); string(got) != want {
+			t.Errorf("access = %s, want %s", got, want)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"user-1"}`))
+	}))
+	defer ts.Close()
+
+	res, err := CreateDatabaseCredential(context.Background(), newTestClient(ts.URL), &CreateDatabaseCredentialRequest{
+		OrganizationId: "org",
+		ProjectId:      "proj",
+		ClusterId:      "cluster",
+		Name:           "ci",
+		Password:       "Secret-123",
+		Access: []Access{{
+			Privileges: []string{"data_reader"},
+			Resources: &AccessResources{Buckets: []BucketAccess{{
+				Name:   "b",
+				Scopes: []ScopeAccess{{Name: "s", Collections: []string{"c"}}},
+			}}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("CreateDatabaseCredential() error = %v", err)
+	}
+	if res.Id != "user-1" {
+		t.Fatalf("id = %q, want user-1", res.Id)
+	}
+}
+
+func TestDeleteDatabaseCredential_NotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/v4/organizations/org/projects/proj/clusters/cluster/users/user-1" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	err := DeleteDatabaseCredential(context.Background(), newTestClient(ts.URL), &DeleteDatabaseCredentialRequest{
+		OrganizationId: "org",
+		ProjectId:      "proj",
+		ClusterId:      "cluster",
+		Id:             "user-1",
+	})
+	if !apiclient.IsNotFound(err) {
+		t.Fatalf("DeleteDatabaseCredential() error = %v, want not found", err)
+	}
+}
@@ -0,0 +1,95 @@
+package indexes
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// IndexBuildResponseError is an error the query service reported in the body of a BUILD INDEX
+// request it otherwise accepted, for example because some of the indexes were already building.
+type IndexBuildResponseError struct {
+	// Messages are the individual errors in the payload.
+	Messages []string
+	// IndexNames are the indexes of the statement the errors name. When the errors name none of
+	// them, every index of the statement is listed, as it cannot be told which ones started.
+	IndexNames []string
+}
+
+func (e *IndexBuildResponseError) Error() string {
+	return "query service reported: " + strings.Join(e.Messages, "; ")
+}
+
+// queryError is the shape of an error entry returned by the query service.
+type queryError struct {
+	Code    any    `json:"code"`
+	Msg     string `json:"msg"`
+	Message string `json:"message"`
+}
+
+func (e queryError) text() string {
+	msg := e.Msg
+	if msg == "" {
+		msg = e.Message
+	}
+	if e.Code == nil {
+		return msg
+	}
+	return fmt.Sprintf("%v: %s", e.Code, msg)
+}
+
+// parseIndexBuildError parses the error payload of a BUILD INDEX response for the indexes in
+// batch. The payload is either plain text, a query service error object, or a list of them.
+func parseIndexBuildError(payload string, batch []string) *IndexBuildResponseError {
+	var messages []string
+	var list []queryError
+	var single queryError
+	switch trimmed := strings.TrimSpace(payload); {
+	case strings.HasPrefix(trimmed, "[") && json.Unmarshal([]byte(trimmed), &list) == nil:
+		for _, e := range list {
+			messages = append(messages, e.text())
+		}
+	case strings.HasPrefix(trimmed, "{") && json.Unmarshal([]byte(trimmed), &single) == nil:
+		messages = append(messages, single.text())
+	default:
+		messages = append(messages, trimmed)
+	}
+
+	var named []string
+	for _, name := range batch {
+		for _, msg := range messages {
+			if mentionsIndex(msg, name) {
+				named = append(named, name)
+				break
+			}
+		}
+	}
+	if len(named) == 0 {
+		named = batch
+	}
+	return &IndexBuildResponseError{Messages: messages, IndexNames: named}
+}
+
+// mentionsIndex reports whether msg contains name as a whole word, so that "idx1" is not
+// found in a message about "idx10".
+func mentionsIndex(msg, name string) bool {
+	if name == "" {
+		return false
+	}
+	for offset := 0; ; {
+		i := strings.Index(msg[offset:], name)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(name)
+		if (start == 0 || !isIdentifierByte(msg[start-1])) && (end == len(msg) || !isIdentifierByte(msg[end])) {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+func isIdentifierByte(b byte) bool {
+	return b == '_' || b == '-' || b == '#' || b >= 0x80 ||
+		(b >= '0' && b <= '9') || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
@@ -0,0 +1,83 @@
+package indexes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseIndexBuildError(t *testing.T) {
+	batch := []string{"idx1", "idx10", "idx2"}
+	tests := []struct {
+		name         string
+		payload      string
+		wantMessages string
+		wantNames    string
+	}{
+		{
+			name:         "plain text",
+			payload:      "Build index fails. Index idx10 is already being built.",
+			wantMessages: "[Build index fails. Index idx10 is already being built.]",
+			wantNames:    "[idx10]",
+		},
+		{
+			name:         "error list",
+			payload:      `[{"code":5000,"msg":"GSI index idx1 not found."},{"code":5000,"msg":"Index ` + "`idx2`" + ` is building"}]`,
+			wantMessages: "[5000: GSI index idx1 not found. 5000: Index `idx2` is building]",
+			wantNames:    "[idx1 idx2]",
+		},
+		{
+			name:         "error object",
+			payload:      `{"code":"capacity","message":"not enough memory for idx2"}`,
+			wantMessages: "[capacity: not enough memory for idx2]",
+			wantNames:    "[idx2]",
+		},
+		{
+			name:         "no index named",
+			payload:      "indexer unavailable",
+			wantMessages: "[indexer unavailable]",
+			wantNames:    "[idx1 idx10 idx2]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseIndexBuildError(tt.payload, batch)
+			if fmt.Sprint(got.Messages) != tt.wantMessages {
+				t.Errorf("messages = %v, want %s", got.Messages, tt.wantMessages)
+			}
+			if fmt.Sprint(got.IndexNames) != tt.wantNames {
+				t.Errorf("index names = %v, want %s", got.IndexNames, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestBuildDeferredIndexes_ResponseError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"error":"Index idx2 is already being built"}`))
+	}))
+	defer ts.Close()
+
+	res, err := BuildDeferredIndexes(context.Background(), newTestClient(ts.URL), &IndexBuildRequest{
+		Bucket:     "b",
+		Scope:      "s",
+		Collection: "coll",
+		IndexNames: []string{"idx1", "idx2", "idx3"},
+	})
+
+	var batchErr *IndexBuildBatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("BuildDeferredIndexes() error = %v, want an *IndexBuildBatchError", err)
+	}
+	if got := fmt.Sprint(res.Built); got != "[idx1 idx3]" {
+		t.Errorf("built = %s, want [idx1 idx3]", got)
+	}
+	var respErr *IndexBuildResponseError
+	if len(res.Failed) != 1 || !errors.As(res.Failed[0].Err, &respErr) || fmt.Sprint(res.Failed[0].IndexNames) != "[idx2]" {
+		t.Fatalf("failed = %+v, want idx2 with an *IndexBuildResponseError", res.Failed)
+	}
+}
@@ -0,0 +1,114 @@
+package indexes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
+	"github.com/cdsre/terraform-provider-capellaextras/api/indexes/n1ql"
+)
+
+type CreateIndexRequest struct {
+	OrganizationId string
+	ProjectId      string
+	ClusterId      string
+	Bucket         string
+	Scope          string
+	Collection     string
+	IndexName      string
+	// IsPrimary creates a primary index; Keys, Where and PartitionBy must then be empty.
+	IsPrimary bool
+	// Keys are N1QL index key expressions. Unlike the index name and keyspace, which are
+	// always quoted, they are used verbatim, e.g. "`airline`" or
+	// "DISTINCT ARRAY `s`.`day` FOR `s` IN `schedule` END" for an array index.
+	Keys []string
+	// Where is the condition of a partial index.
+	Where string
+	// PartitionBy is the partitioning expression, e.g. "HASH(meta().id)".
+	PartitionBy string
+	With        *IndexWith
+}
+
+// IndexWith holds the WITH options of a CREATE INDEX statement. Unset fields are omitted.
+type IndexWith struct {
+	DeferBuild   *bool    `json:"defer_build,omitempty"`
+	NumReplica   *int64   `json:"num_replica,omitempty"`
+	NumPartition *int64   `json:"num_partition,omitempty"`
+	Nodes        []string `json:"nodes,omitempty"`
+}
+
+type DropIndexRequest struct {
+	OrganizationId string
+	ProjectId      string
+	ClusterId      string
+	Bucket         string
+	Scope          string
+	Collection     string
+	IndexName      string
+}
+
+// CreateIndexStatement returns the CREATE INDEX statement for req.
+func CreateIndexStatement(req *CreateIndexRequest) (string, error) {
+	var b strings.Builder
+	keyspace := n1ql.Keyspace(req.Bucket, req.Scope, req.Collection)
+
+	if req.IsPrimary {
+		fmt.Fprintf(&b, "CREATE PRIMARY INDEX %s ON %s", n1ql.QuoteIdentifier(req.IndexName), keyspace)
+	} else {
+		if len(req.Keys) == 0 {
+			return "", fmt.Errorf("index %q needs at least one key unless it is a primary index", req.IndexName)
+		}
+		fmt.Fprintf(&b, "CREATE INDEX %s ON %s(%s)", n1ql.QuoteIdentifier(req.IndexName), keyspace, strings.Join(req.Keys, ", "))
+		if req.PartitionBy != "" {
+			fmt.Fprintf(&b, " PARTITION BY %s", req.PartitionBy)
+		}
+		if req.Where != "" {
+			fmt.Fprintf(&b, " WHERE %s", req.Where)
+		}
+	}
+
+	if req.With != nil {
+		with, err := json.Marshal(req.With)
+		if err != nil {
+			return "", err
+		}
+		if string(with) != "{}" {
+			fmt.Fprintf(&b, " WITH %s", with)
+		}
+	}
+	return b.String(), nil
+}
+
+// CreateIndex runs the CREATE INDEX statement for req.
+func CreateIndex(ctx context.Context, c *apiclient.Client, req *CreateIndexRequest) (*IndexBuildResponse, error) {
+	stmt, err := CreateIndexStatement(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var res *IndexBuildResponse
+	path := fmt.Sprintf("v4/organizations/%s/projects/%s/clusters/%s/queryService/indexes",
+		req.OrganizationId,
+		req.ProjectId,
+		req.ClusterId,
+	)
+	_, err = c.Post(ctx, path, IndexDefinition{Definition: stmt}, &res)
+	return res, err
+}
+
+// DropIndex drops a single index.
+func DropIndex(ctx context.Context, c *apiclient.Client, req *DropIndexRequest) error {
+	path := fmt.Sprintf("v4/organizations/%s/projects/%s/clusters/%s/queryService/indexes/%s",
+		req.OrganizationId,
+		req.ProjectId,
+		req.ClusterId,
+		url.PathEscape(req.IndexName),
+	)
+
+	_, err := c.Do(ctx, http.MethodDelete, path, keyspaceParams(req.Bucket, req.Scope, req.Collection), nil, nil)
+	return err
+}
@@ -0,0 +1,61 @@
+package indexes
+
+import "testing"
+
+func TestCreateIndexStatement(t *testing.T) {
+	deferBuild := true
+	numReplica := int64(2)
+	tests := []struct {
+		name string
+		req  CreateIndexRequest
+		want string
+	}{
+		{
+			name: "secondary",
+			req:  CreateIndexRequest{Bucket: "b", Scope: "s", Collection: "c", IndexName: "idx", Keys: []string{"a", "b"}},
+			want: "CREATE INDEX `idx` ON `b`.`s`.`c`(a, b)",
+		},
+		{
+			name: "partial partitioned with options",
+			req: CreateIndexRequest{
+				Bucket: "b", Scope: "s", Collection: "c", IndexName: "idx",
+				Keys:        []string{"DISTINCT ARRAY v FOR v IN tags END"},
+				Where:       "type = 'hotel'",
+				PartitionBy: "HASH(meta().id)",
+				With:        &IndexWith{DeferBuild: &deferBuild, NumReplica: &numReplica, Nodes: []string{"n1:8091"}},
+			},
+			want: "CREATE INDEX `idx` ON `b`.`s`.`c`(DISTINCT ARRAY v FOR v IN tags END) PARTITION BY HASH(meta().id) " +
+				`WHERE type = 'hotel' WITH {"defer_build":true,"num_replica":2,"nodes":["n1:8091"]}`,
+		},
+		{
+			name: "primary with empty options",
+			req:  CreateIndexRequest{Bucket: "b", Scope: "_default", Collection: "_default", IndexName: "#primary", IsPrimary: true, With: &IndexWith{}},
+			want: "CREATE PRIMARY INDEX `#primary` ON `b`.`_default`.`_default`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CreateIndexStatement(&tt.req)
+			if err != nil {
+				t.Fatalf("CreateIndexStatement() error = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("CreateIndexStatement() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateIndexStatement_noKeys(t *testing.T) {
+	if _, err := CreateIndexStatement(&CreateIndexRequest{IndexName: "idx"}); err == nil {
+		t.Fatal("CreateIndexStatement() error = nil, want an error for a secondary index without keys")
+	}
+}
+
+func TestBuildIndexStatement(t *testing.T) {
+	got := BuildIndexStatement("travel-sample", "_default", "_default", []string{"idx-1", "select", "a`) ; DROP INDEX x"})
+	want := "BUILD INDEX ON `travel-sample`.`_default`.`_default`(`idx-1`, `select`, `a``) ; DROP INDEX x`)"
+	if got != want {
+		t.Fatalf("BuildIndexStatement() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,65 @@
+package indexes
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
+)
+
+// fetchIndexes calls fetch for every name in indexNames, at most concurrency at a time, and
+// returns the results keyed by index name. Indexes that do not exist are omitted from the
+// result rather than reported as an error. Any other failure cancels the outstanding calls and
+// is returned, naming the index with what; the results fetched so far are returned alongside it.
+func fetchIndexes[T any](ctx context.Context, indexNames []string, concurrency int, what string, fetch func(ctx context.Context, indexName string) (T, error)) (map[string]T, error) {
+	if concurrency <= 0 {
+		concurrency = DefaultStatusConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	results := make(map[string]T, len(indexNames))
+	sem := make(chan struct{}, concurrency)
+
+	for _, indexName := range indexNames {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(indexName string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			res, err := fetch(ctx, indexName)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				results[indexName] = res
+			case apiclient.IsNotFound(err):
+			case firstErr == nil:
+				firstErr = fmt.Errorf("cannot get %s for index %q: %w", what, indexName, err)
+				cancel()
+			}
+		}(indexName)
+	}
+	wg.Wait()
+
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = ctx.Err()
+	}
+	return results, firstErr
+}
@@ -7,10 +7,18 @@ import (
 	"strings"
 
 	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
+	"github.com/cdsre/terraform-provider-capellaextras/api/indexes/n1ql"
 )
 
+// IndexBuildStatusResponse is the build status of an index. Only Status is always reported;
+// the other fields are left unset when the API does not provide them.
 type IndexBuildStatusResponse struct {
 	Status string
+	// Progress is the build progress as a percentage.
+	Progress   *int64   `json:"progress,omitempty"`
+	NumReplica *int64   `json:"numReplica,omitempty"`
+	Hosts      []string `json:"hosts,omitempty"`
+	Error      string   `json:"error,omitempty"`
 }
 
 type IndexBuildStatusRequest struct {
@@ -31,6 +39,9 @@ type IndexBuildRequest struct {
 	IndexNames     []string
 	Scope          string
 	Collection     string
+	// MaxIndexesPerBuild splits IndexNames into BUILD INDEX statements of at most this many
+	// indexes each. Zero builds every index in a single statement.
+	MaxIndexesPerBuild int
 }
 type IndexDefinition struct {
 	Definition string
@@ -40,8 +51,10 @@ type IndexBuildResponse struct {
 	Error *string `json:"error,omitempty"`
 }
 
+// GetIndexBuildStatus returns the build status of a single index. An empty or null response body
+// yields a zero IndexBuildStatusResponse rather than nil.
 func GetIndexBuildStatus(ctx context.Context, c *apiclient.Client, req *IndexBuildStatusRequest) (*IndexBuildStatusResponse, error) {
-	var res *IndexBuildStatusResponse
+	var res IndexBuildStatusResponse
 	path := fmt.Sprintf("v4/organizations/%s/projects/%s/clusters/%s/queryService/indexBuildStatus/%s",
 		req.OrganizationId,
 		req.ProjectId,
@@ -54,25 +67,99 @@ func GetIndexBuildStatus(ctx context.Context, c *apiclient.Client, req *IndexBui
 		"collection": req.Collection,
 	}
 
-	_, err := c.Get(ctx, path, params, &res)
-	return res, err
+	if _, err := c.GetLenient(ctx, path, params, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// BuildIndexStatement returns the BUILD INDEX statement for indexNames in a keyspace.
+func BuildIndexStatement(bucket, scope, collection string, indexNames []string) string {
+	return fmt.Sprintf("BUILD INDEX ON %s(%s)", n1ql.Keyspace(bucket, scope, collection), n1ql.QuoteIdentifiers(indexNames))
+}
+
+// IndexBuildResult reports the outcome of BuildDeferredIndexes.
+type IndexBuildResult struct {
+	// Built lists the indexes whose builds were started, in request order.
+	Built []string
+	// Failed lists the batches whose BUILD INDEX statement was rejected or reported errors.
+	Failed []IndexBuildBatchFailure
 }
 
-func BuildDeferredIndexes(ctx context.Context, c *apiclient.Client, req *IndexBuildRequest) (*IndexBuildResponse, error) {
-	var res *IndexBuildResponse
-	def := IndexDefinition{Definition: fmt.Sprintf(
-		"BUILD INDEX ON `%s`.`%s`.`%s`(%s)",
-		req.Bucket,
-		req.Scope,
-		req.Collection,
-		strings.Join(req.IndexNames, ", "),
-	)}
+// IndexBuildBatchFailure is a BUILD INDEX statement that failed and the indexes that failed with
+// it: every index of the statement, or only those named by an *IndexBuildResponseError.
+type IndexBuildBatchFailure struct {
+	IndexNames []string
+	Err        error
+}
+
+// IndexBuildBatchError is returned by BuildDeferredIndexes when at least one batch failed.
+type IndexBuildBatchError struct {
+	Failed  int
+	Batches int
+}
+
+func (e *IndexBuildBatchError) Error() string {
+	return fmt.Sprintf("%d of %d index build batches failed", e.Failed, e.Batches)
+}
 
+// BuildDeferredIndexes builds req.IndexNames in batches of at most req.MaxIndexesPerBuild,
+// submitting them one after another. A failed batch does not stop the remaining ones: the
+// result records which indexes were built and which batches failed, and an
+// *IndexBuildBatchError is returned if any did.
+func BuildDeferredIndexes(ctx context.Context, c *apiclient.Client, req *IndexBuildRequest) (*IndexBuildResult, error) {
 	path := fmt.Sprintf("v4/organizations/%s/projects/%s/clusters/%s/queryService/indexes",
 		req.OrganizationId,
 		req.ProjectId,
 		req.ClusterId,
 	)
-	_, err := c.Post(ctx, path, def, &res)
-	return res, err
+
+	batches := batchIndexNames(req.IndexNames, req.MaxIndexesPerBuild)
+	result := &IndexBuildResult{}
+	for _, batch := range batches {
+		// Once ctx is done every request fails, so report the rest without sending them.
+		err := ctx.Err()
+		var res *IndexBuildResponse
+		if err == nil {
+			def := IndexDefinition{Definition: BuildIndexStatement(req.Bucket, req.Scope, req.Collection, batch)}
+			_, err = c.Post(ctx, path, def, &res)
+		}
+		if err != nil {
+			result.Failed = append(result.Failed, IndexBuildBatchFailure{IndexNames: batch, Err: err})
+			continue
+		}
+
+		// An accepted statement can still report errors for some of its indexes; the
+		// indexes the errors do not name have started building.
+		if res != nil && res.Error != nil && strings.TrimSpace(*res.Error) != "" {
+			buildErr := parseIndexBuildError(*res.Error, batch)
+			result.Failed = append(result.Failed, IndexBuildBatchFailure{IndexNames: buildErr.IndexNames, Err: buildErr})
+			failed := toSet(buildErr.IndexNames)
+			for _, name := range batch {
+				if !failed[name] {
+					result.Built = append(result.Built, name)
+				}
+			}
+			continue
+		}
+		result.Built = append(result.Built, batch...)
+	}
+
+	if len(result.Failed) > 0 {
+		return result, &IndexBuildBatchError{Failed: len(result.Failed), Batches: len(batches)}
+	}
+	return result, nil
+}
+
+// batchIndexNames splits names into consecutive batches of at most size names. A size of
+// zero or less yields a single batch.
+func batchIndexNames(names []string, size int) [][]string {
+	if size <= 0 || size >= len(names) {
+		return [][]string{names}
+	}
+	batches := make([][]string, 0, (len(names)+size-1)/size)
+	for start := 0; start < len(names); start += size {
+		batches = append(batches, names[start:min(start+size, len(names))])
+	}
+	return batches
 }
@@ -0,0 +1,65 @@
+package indexes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBatchIndexNames(t *testing.T) {
+	names := []string{"a", "b", "c", "d", "e"}
+	tests := map[int]string{
+		0:  "[[a b c d e]]",
+		2:  "[[a b] [c d] [e]]",
+		5:  "[[a b c d e]]",
+		10: "[[a b c d e]]",
+	}
+	for size, want := range tests {
+		if got := fmt.Sprint(batchIndexNames(names, size)); got != want {
+			t.Errorf("batchIndexNames(size=%d) = %s, want %s", size, got, want)
+		}
+	}
+}
+
+func TestBuildDeferredIndexes_Batches(t *testing.T) {
+	var statements []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var def IndexDefinition
+		_ = json.NewDecoder(r.Body).Decode(&def)
+		statements = append(statements, def.Definition)
+		if strings.Contains(def.Definition, "`c`") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	res, err := BuildDeferredIndexes(context.Background(), newTestClient(ts.URL), &IndexBuildRequest{
+		Bucket:             "b",
+		Scope:              "s",
+		Collection:         "coll",
+		IndexNames:         []string{"a", "b", "c", "d", "e"},
+		MaxIndexesPerBuild: 2,
+	})
+
+	var batchErr *IndexBuildBatchError
+	if !errors.As(err, &batchErr) || batchErr.Failed != 1 || batchErr.Batches != 3 {
+		t.Fatalf("BuildDeferredIndexes() error = %v, want 1 of 3 batches failed", err)
+	}
+	if len(statements) != 3 {
+		t.Fatalf("sent %d statements, want 3: %v", len(statements), statements)
+	}
+	if got := fmt.Sprint(res.Built); got != "[a b e]" {
+		t.Errorf("built = %s, want [a b e]", got)
+	}
+	if len(res.Failed) != 1 || fmt.Sprint(res.Failed[0].IndexNames) != "[c d]" {
+		t.Errorf("failed = %+v, want one batch of [c d]", res.Failed)
+	}
+}
@@ -0,0 +1,125 @@
+package indexes
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
+)
+
+type ListIndexesRequest struct {
+	OrganizationId string
+	ProjectId      string
+	ClusterId      string
+	Bucket         string
+	Scope          string
+	Collection     string
+}
+
+// IndexDDL is an entry in the index list of a keyspace: the index name and its CREATE INDEX statement.
+type IndexDDL struct {
+	IndexName  string `json:"indexName"`
+	Definition string `json:"definition"`
+}
+
+type ListIndexesResponse struct {
+	Definitions []IndexDDL `json:"definitions"`
+}
+
+type GetIndexesRequest struct {
+	OrganizationId string
+	ProjectId      string
+	ClusterId      string
+	Bucket         string
+	Scope          string
+	Collection     string
+	IndexNames     []string
+	// Concurrency bounds the number of parallel requests. Zero means DefaultStatusConcurrency.
+	Concurrency int
+}
+
+type GetIndexRequest struct {
+	OrganizationId string
+	ProjectId      string
+	ClusterId      string
+	Bucket         string
+	Scope          string
+	Collection     string
+	IndexName      string
+}
+
+// Index describes the properties of a single GSI index.
+type Index struct {
+	IndexName      string   `json:"indexName"`
+	Bucket         string   `json:"bucket,omitempty"`
+	Scope          string   `json:"scope,omitempty"`
+	Collection     string   `json:"collection,omitempty"`
+	IsPrimary      bool     `json:"isPrimary"`
+	SecondaryExprs []string `json:"secondaryExprs,omitempty"`
+	Where          string   `json:"where,omitempty"`
+	PartitionBy    string   `json:"partitionBy,omitempty"`
+	NumReplica     int64    `json:"numReplica"`
+	NumPartition   int64    `json:"numPartition,omitempty"`
+	DeferBuild     bool     `json:"deferBuild"`
+	Status         string   `json:"status,omitempty"`
+}
+
+func keyspaceParams(bucket, scope, collection string) map[string]string {
+	return map[string]string{
+		"bucket":     bucket,
+		"scope":      scope,
+		"collection": collection,
+	}
+}
+
+// ListIndexes returns the name and CREATE INDEX statement of every index in a keyspace. Response
+// fields that are not modelled are ignored.
+func ListIndexes(ctx context.Context, c *apiclient.Client, req *ListIndexesRequest) (*ListIndexesResponse, error) {
+	var res *ListIndexesResponse
+	path := fmt.Sprintf("v4/organizations/%s/projects/%s/clusters/%s/queryService/indexes",
+		req.OrganizationId,
+		req.ProjectId,
+		req.ClusterId,
+	)
+
+	_, err := c.GetLenient(ctx, path, keyspaceParams(req.Bucket, req.Scope, req.Collection), &res)
+	if err == nil && res == nil {
+		res = &ListIndexesResponse{}
+	}
+	return res, err
+}
+
+// GetIndex returns the properties of a single index. Response fields that are not modelled are
+// ignored, and an empty or null response body yields a zero Index rather than nil.
+func GetIndex(ctx context.Context, c *apiclient.Client, req *GetIndexRequest) (*Index, error) {
+	var res Index
+	path := fmt.Sprintf("v4/organizations/%s/projects/%s/clusters/%s/queryService/indexes/%s",
+		req.OrganizationId,
+		req.ProjectId,
+		req.ClusterId,
+		url.PathEscape(req.IndexName),
+	)
+
+	if _, err := c.GetLenient(ctx, path, keyspaceParams(req.Bucket, req.Scope, req.Collection), &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetIndexes fetches the properties of every index in req.IndexNames in parallel and returns
+// them keyed by index name. Like GetIndexBuildStatuses, indexes that do not exist are omitted
+// and any other failure is returned alongside the indexes fetched so far.
+func GetIndexes(ctx context.Context, c *apiclient.Client, req *GetIndexesRequest) (map[string]*Index, error) {
+	return fetchIndexes(ctx, req.IndexNames, req.Concurrency, "properties", func(ctx context.Context, indexName string) (*Index, error) {
+		return GetIndex(ctx, c, &GetIndexRequest{
+			OrganizationId: req.OrganizationId,
+			ProjectId:      req.ProjectId,
+			ClusterId:      req.ClusterId,
+			Bucket:         req.Bucket,
+			Scope:          req.Scope,
+			Collection:     req.Collection,
+			IndexName:      indexName,
+		})
+	})
+}
@@ -0,0 +1,108 @@
+package indexes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestListIndexes(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v4/organizations/org/projects/proj/clusters/cluster/queryService/indexes" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("collection"); got != "route" {
+			t.Errorf("collection = %q, want route", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"definitions":[{"indexName":"idx1","definition":"CREATE INDEX idx1 ON b(a)"}]}`))
+	}))
+	defer ts.Close()
+
+	res, err := ListIndexes(context.Background(), newTestClient(ts.URL), &ListIndexesRequest{
+		OrganizationId: "org",
+		ProjectId:      "proj",
+		ClusterId:      "cluster",
+		Bucket:         "b",
+		Scope:          "inventory",
+		Collection:     "route",
+	})
+	if err != nil {
+		t.Fatalf("ListIndexes() error = %v", err)
+	}
+	if len(res.Definitions) != 1 || res.Definitions[0].IndexName != "idx1" {
+		t.Fatalf("definitions = %+v, want idx1", res.Definitions)
+	}
+}
+
+func TestGetIndex(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, _ := url.PathUnescape(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+		if name != "#primary" {
+			t.Errorf("index name = %q, want #primary", name)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"indexName":"#primary","isPrimary":true,"numReplica":1,"deferBuild":false,"status":"Ready"}`))
+	}))
+	defer ts.Close()
+
+	idx, err := GetIndex(context.Background(), newTestClient(ts.URL), &GetIndexRequest{
+		OrganizationId: "org",
+		ProjectId:      "proj",
+		ClusterId:      "cluster",
+		Bucket:         "b",
+		IndexName:      "#primary",
+	})
+	if err != nil {
+		t.Fatalf("GetIndex() error = %v", err)
+	}
+	if !idx.IsPrimary || idx.NumReplica != 1 || idx.Status != "Ready" {
+		t.Fatalf("index = %+v", idx)
+	}
+}
+
+func TestGetIndex_EmptyBody(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	idx, err := GetIndex(context.Background(), newTestClient(ts.URL), &GetIndexRequest{IndexName: "idx1"})
+	if err != nil {
+		t.Fatalf("GetIndex() error = %v", err)
+	}
+	if idx == nil || idx.IndexName != "" {
+		t.Fatalf("GetIndex() = %+v, want an empty index", idx)
+	}
+}
+
+func TestGetIndexes(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		if name == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		// lastScanTime is not modelled and must not fail decoding.
+		_, _ = w.Write([]byte(`{"indexName":"` + name + `","isPrimary":false,"numReplica":0,"deferBuild":true,"status":"Created","lastScanTime":"NA"}`))
+	}))
+	defer ts.Close()
+
+	got, err := GetIndexes(context.Background(), newTestClient(ts.URL), &GetIndexesRequest{
+		OrganizationId: "org",
+		ProjectId:      "proj",
+		ClusterId:      "cluster",
+		Bucket:         "b",
+		IndexNames:     []string{"idx1", "missing", "idx2"},
+	})
+	if err != nil {
+		t.Fatalf("GetIndexes() error = %v", err)
+	}
+	if len(got) != 2 || got["idx1"].Status != "Created" || got["idx2"].IndexName != "idx2" {
+		t.Fatalf("indexes = %+v, want idx1 and idx2", got)
+	}
+}
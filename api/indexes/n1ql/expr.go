@@ -0,0 +1,625 @@
+package n1ql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// exprKeywords are the words read as keywords rather than identifiers. Keywords and function
+// names are case-insensitive; identifiers are not.
+var exprKeywords = map[string]bool{
+	"all": true, "and": true, "any": true, "array": true, "asc": true, "between": true,
+	"case": true, "desc": true, "distinct": true, "else": true, "end": true, "every": true,
+	"false": true, "for": true, "in": true, "include": true, "is": true, "known": true,
+	"like": true, "missing": true, "not": true, "null": true, "or": true, "satisfies": true,
+	"some": true, "then": true, "true": true, "valued": true, "when": true, "within": true,
+}
+
+// CanonicalExpr returns expr in a canonical form, so that two expressions that differ only in the
+// way the query service rewrites them have the same canonical form. Whitespace, redundant
+// parentheses, identifier quoting, the quote character of string literals and the case of keywords
+// and function names are normalised. Identifier case and the grouping of operators are kept, so
+// "Name" and "name", or "(a + b) * c" and "a + b * c", have different canonical forms. A chain of
+// the associative operators AND, OR, +, * and || is flattened, however it is grouped.
+//
+// CanonicalExpr also accepts the INCLUDE MISSING, ASC and DESC modifiers of an index key. An
+// expression it cannot parse is returned as its normalised tokens, keeping every parenthesis.
+func CanonicalExpr(expr string) string {
+	tokens, err := tokenizeExpr(expr)
+	if err != nil {
+		return expr
+	}
+	if len(tokens) == 0 {
+		return ""
+	}
+
+	p := &exprParser{tokens: tokens}
+	canonical := p.parseIndexKey()
+	if p.err != nil {
+		return canonicalTokens(tokens)
+	}
+	return canonical
+}
+
+type exprTokenKind int
+
+const (
+	tokenEOF exprTokenKind = iota
+	tokenIdent
+	tokenKeyword
+	tokenString
+	tokenNumber
+	tokenPunct
+)
+
+type exprToken struct {
+	kind exprTokenKind
+	// text is the unquoted name of an identifier, the lower-case keyword, the value of a string,
+	// or the number or punctuation as written.
+	text string
+	// raw is the token as written.
+	raw string
+}
+
+// exprOperators are the operators and punctuation, longest first.
+var exprOperators = []string{"==", "!=", "<>", "<=", ">=", "||", "=", "<", ">", "+", "-", "*", "/", "%", "(", ")", "[", "]", "{", "}", ",", ".", ":"}
+
+// operatorAliases maps operators to the spelling the canonical form uses.
+var operatorAliases = map[string]string{"==": "=", "<>": "!="}
+
+func tokenizeExpr(expr string) ([]exprToken, error) {
+	var tokens []exprToken
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '`' || c == '\'' || c == '"':
+			end, err := closingQuote(expr, i)
+			if err != nil {
+				return nil, err
+			}
+			raw := expr[i : end+1]
+			if c == '`' {
+				name, err := UnquoteIdentifier(raw)
+				if err != nil {
+					return nil, err
+				}
+				tokens = append(tokens, exprToken{kind: tokenIdent, text: name, raw: raw})
+			} else {
+				tokens = append(tokens, exprToken{kind: tokenString, text: unquoteString(raw), raw: raw})
+			}
+			i = end + 1
+		case isDigit(c):
+			j := i + 1
+			for j < len(expr) && (isDigit(expr[j]) || expr[j] == '.' ||
+				((expr[j] == 'e' || expr[j] == 'E') && j+1 < len(expr)) ||
+				((expr[j] == '+' || expr[j] == '-') && (expr[j-1] == 'e' || expr[j-1] == 'E'))) {
+				j++
+			}
+			tokens = append(tokens, exprToken{kind: tokenNumber, text: strings.ToLower(expr[i:j]), raw: expr[i:j]})
+			i = j
+		case isWordByte(c):
+			j := i + 1
+			for j < len(expr) && (isWordByte(expr[j]) || isDigit(expr[j])) {
+				j++
+			}
+			word := expr[i:j]
+			if lower := strings.ToLower(word); exprKeywords[lower] {
+				tokens = append(tokens, exprToken{kind: tokenKeyword, text: lower, raw: word})
+			} else {
+				tokens = append(tokens, exprToken{kind: tokenIdent, text: word, raw: word})
+			}
+			i = j
+		default:
+			op := ""
+			for _, o := range exprOperators {
+				if strings.HasPrefix(expr[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at offset %d", c, i)
+			}
+			text := op
+			if alias, ok := operatorAliases[op]; ok {
+				text = alias
+			}
+			tokens = append(tokens, exprToken{kind: tokenPunct, text: text, raw: op})
+			i += len(op)
+		}
+	}
+	return tokens, nil
+}
+
+// closingQuote returns the offset of the quote that closes the quoted token starting at start. A
+// doubled quote and a backslash escape do not close it.
+func closingQuote(expr string, start int) (int, error) {
+	quote := expr[start]
+	for i := start + 1; i < len(expr); i++ {
+		switch expr[i] {
+		case '\\':
+			i++
+		case quote:
+			if i+1 < len(expr) && expr[i+1] == quote {
+				i++
+				continue
+			}
+			return i, nil
+		}
+	}
+	return 0, errors.New("unterminated quoted token")
+}
+
+// unquoteString returns the value of a quoted string literal. Escaped and doubled quotes become
+// plain quotes; other escape sequences are kept as written.
+func unquoteString(raw string) string {
+	quote := raw[0]
+	body := raw[1 : len(raw)-1]
+	var b strings.Builder
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case c == '\\' && i+1 < len(body) && (body[i+1] == '\'' || body[i+1] == '"'):
+			b.WriteByte(body[i+1])
+			i++
+		case c == '\\' && i+1 < len(body):
+			b.WriteString(body[i : i+2])
+			i++
+		case c == quote && i+1 < len(body) && body[i+1] == quote:
+			b.WriteByte(quote)
+			i++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// quoteString returns value as a double-quoted string literal.
+func quoteString(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+// canonicalTokens joins the canonical spelling of each token, for expressions that cannot be
+// parsed.
+func canonicalTokens(tokens []exprToken) string {
+	parts := make([]string, len(tokens))
+	for i, t := range tokens {
+		switch {
+		case t.kind == tokenIdent && t.raw[0] != '`' && i+1 < len(tokens) && tokens[i+1].raw == "(":
+			parts[i] = strings.ToLower(t.text)
+		case t.kind == tokenIdent:
+			parts[i] = QuoteIdentifier(t.text)
+		case t.kind == tokenString:
+			parts[i] = quoteString(t.text)
+		default:
+			parts[i] = t.text
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Operator precedence, from the loosest binding to the tightest.
+const (
+	precOr = iota + 1
+	precAnd
+	precNot
+	precCompare
+	precConcat
+	precAdd
+	precMul
+	precUnary
+)
+
+// associativeOperators are the operators whose chains are flattened.
+var associativeOperators = map[string]bool{"and": true, "or": true, "+": true, "*": true, "||": true}
+
+// exprNode is a parsed expression. A chain of one associative operator keeps its operands, so a
+// chain that continues across parentheses is flattened; any other expression is its canonical text.
+type exprNode struct {
+	op       string
+	operands []string
+	text     string
+}
+
+func (n exprNode) String() string {
+	if n.op == "" {
+		return n.text
+	}
+	return "(" + strings.Join(n.operands, " "+n.op+" ") + ")"
+}
+
+// chainOperands returns the operands n contributes to a chain of op.
+func (n exprNode) chainOperands(op string) []string {
+	if n.op == op {
+		return n.operands
+	}
+	return []string{n.String()}
+}
+
+func textNode(format string, args ...any) exprNode {
+	return exprNode{text: fmt.Sprintf(format, args...)}
+}
+
+// exprParser is a precedence-climbing parser that renders each expression in canonical form as it
+// goes. The first error is kept in err and stops the parse.
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+	err    error
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.peekAt(0)
+}
+
+func (p *exprParser) peekAt(offset int) exprToken {
+	if p.err != nil || p.pos+offset >= len(p.tokens) {
+		return exprToken{kind: tokenEOF}
+	}
+	return p.tokens[p.pos+offset]
+}
+
+func (p *exprParser) next() exprToken {
+	t := p.peek()
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is the given keyword or punctuation.
+func (p *exprParser) accept(kind exprTokenKind, text string) bool {
+	if t := p.peek(); t.kind == kind && t.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) expect(kind exprTokenKind, text string) {
+	if !p.accept(kind, text) {
+		p.fail()
+	}
+}
+
+// fail records that the next token is unexpected.
+func (p *exprParser) fail() {
+	p.unexpected(p.peek())
+}
+
+// unexpected records that t is unexpected.
+func (p *exprParser) unexpected(t exprToken) {
+	if p.err != nil {
+		return
+	}
+	if t.kind == tokenEOF {
+		p.err = errors.New("unexpected end of expression")
+	} else {
+		p.err = fmt.Errorf("unexpected %q", t.raw)
+	}
+}
+
+// parseIndexKey parses an expression followed by any index key modifiers and the end of input.
+func (p *exprParser) parseIndexKey() string {
+	key := p.parseExpr(precOr).String()
+	var includeMissing, desc bool
+	for p.err == nil && p.peek().kind != tokenEOF {
+		switch {
+		case p.accept(tokenKeyword, "include"):
+			p.expect(tokenKeyword, "missing")
+			includeMissing = true
+		case p.accept(tokenKeyword, "asc"):
+		case p.accept(tokenKeyword, "desc"):
+			desc = true
+		default:
+			p.fail()
+		}
+	}
+	if includeMissing {
+		key += " include missing"
+	}
+	if desc {
+		key += " desc"
+	}
+	return key
+}
+
+// parseExpr parses an expression whose binary operators bind at least as tightly as minPrec.
+func (p *exprParser) parseExpr(minPrec int) exprNode {
+	left := p.parsePrefix()
+	for p.err == nil {
+		t := p.peek()
+		not := ""
+		if t.kind == tokenKeyword && t.text == "not" {
+			if next := p.peekAt(1); next.kind == tokenKeyword && (next.text == "like" || next.text == "in" || next.text == "within" || next.text == "between") {
+				not = "not "
+				t = next
+			}
+		}
+		op, prec := infixOperator(t)
+		if prec == 0 || prec < minPrec {
+			return left
+		}
+		if not != "" {
+			p.next()
+		}
+		p.next()
+
+		switch op {
+		case "between":
+			low := p.parseExpr(precConcat)
+			p.expect(tokenKeyword, "and")
+			high := p.parseExpr(precConcat)
+			left = textNode("(%s %sbetween %s and %s)", left, not, low, high)
+		case "is":
+			if p.accept(tokenKeyword, "not") {
+				not = "not "
+			}
+			switch v := p.next(); {
+			case v.kind == tokenKeyword && (v.text == "null" || v.text == "missing" || v.text == "valued"):
+				left = textNode("(%s is %s%s)", left, not, v.text)
+			case v.kind == tokenKeyword && v.text == "known":
+				left = textNode("(%s is %svalued)", left, not)
+			default:
+				p.unexpected(v)
+			}
+		default:
+			right := p.parseExpr(prec + 1)
+			if associativeOperators[op] {
+				operands := append(append([]string(nil), left.chainOperands(op)...), right.chainOperands(op)...)
+				left = exprNode{op: op, operands: operands}
+			} else {
+				left = textNode("(%s %s%s %s)", left, not, op, right)
+			}
+		}
+	}
+	return left
+}
+
+// infixOperator returns the binary operator t starts and its precedence, or zero if it does not
+// start one.
+func infixOperator(t exprToken) (string, int) {
+	switch t.kind {
+	case tokenKeyword:
+		switch t.text {
+		case "or":
+			return t.text, precOr
+		case "and":
+			return t.text, precAnd
+		case "like", "in", "within", "between", "is":
+			return t.text, precCompare
+		}
+	case tokenPunct:
+		switch t.text {
+		case "=", "!=", "<", "<=", ">", ">=":
+			return t.text, precCompare
+		case "||":
+			return t.text, precConcat
+		case "+", "-":
+			return t.text, precAdd
+		case "*", "/", "%":
+			return t.text, precMul
+		}
+	}
+	return "", 0
+}
+
+// parsePrefix parses an operand: a literal, identifier, function call, unary operation,
+// parenthesised expression or compound expression, followed by any field and element accesses.
+func (p *exprParser) parsePrefix() exprNode {
+	var n exprNode
+	switch t := p.next(); t.kind {
+	case tokenNumber:
+		n = exprNode{text: t.text}
+	case tokenString:
+		n = exprNode{text: quoteString(t.text)}
+	case tokenIdent:
+		if t.raw[0] != '`' && p.peek().raw == "(" {
+			p.next()
+			n = textNode("%s(%s)", strings.ToLower(t.text), p.parseList(")"))
+		} else {
+			n = exprNode{text: QuoteIdentifier(t.text)}
+		}
+	case tokenKeyword:
+		switch t.text {
+		case "true", "false", "null", "missing":
+			n = exprNode{text: t.text}
+		case "not":
+			n = textNode("(not %s)", p.parseExpr(precNot))
+		case "distinct", "all":
+			// The query service writes DISTINCT ARRAY ... END as distinct (array ... end).
+			parens := 0
+			for p.accept(tokenPunct, "(") {
+				parens++
+			}
+			p.expect(tokenKeyword, "array")
+			n = p.parseArray(t.text + " ")
+			for ; parens > 0; parens-- {
+				p.expect(tokenPunct, ")")
+			}
+		case "array":
+			n = p.parseArray("")
+		case "any", "some", "every":
+			quantifier := t.text
+			if quantifier == "some" {
+				quantifier = "any"
+			}
+			if quantifier == "any" && p.accept(tokenKeyword, "and") {
+				p.expect(tokenKeyword, "every")
+				quantifier = "any and every"
+			}
+			bindings := p.parseBindings()
+			p.expect(tokenKeyword, "satisfies")
+			n = textNode("(%s %s satisfies %s end)", quantifier, bindings, p.parseExpr(precOr))
+			p.expect(tokenKeyword, "end")
+		case "case":
+			n = p.parseCase()
+		default:
+			p.unexpected(t)
+		}
+	case tokenPunct:
+		switch t.text {
+		case "(":
+			n = p.parseExpr(precOr)
+			p.expect(tokenPunct, ")")
+		case "-":
+			n = textNode("(-%s)", p.parseExpr(precUnary))
+		case "[":
+			n = textNode("[%s]", p.parseList("]"))
+		case "{":
+			n = p.parseObject()
+		default:
+			p.unexpected(t)
+		}
+	default:
+		p.unexpected(t)
+	}
+
+	for p.err == nil {
+		switch {
+		case p.accept(tokenPunct, "."):
+			field := p.next()
+			if field.kind != tokenIdent && field.kind != tokenKeyword {
+				p.unexpected(field)
+				return n
+			}
+			name := field.raw
+			if field.kind == tokenIdent {
+				name = field.text
+			}
+			n = textNode("%s.%s", n, QuoteIdentifier(name))
+		case p.accept(tokenPunct, "["):
+			if p.accept(tokenPunct, "*") {
+				p.expect(tokenPunct, "]")
+				n = textNode("%s[*]", n)
+				continue
+			}
+			index := p.parseExpr(precOr).String()
+			if p.accept(tokenPunct, ":") {
+				index += ":" + p.parseExpr(precOr).String()
+			}
+			p.expect(tokenPunct, "]")
+			n = textNode("%s[%s]", n, index)
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+// parseList parses comma-separated expressions up to and including the closing punctuation. A
+// lone * is accepted as in COUNT(*).
+func (p *exprParser) parseList(closing string) string {
+	var items []string
+	if p.accept(tokenPunct, closing) {
+		return ""
+	}
+	if closing == ")" && p.accept(tokenPunct, "*") {
+		p.expect(tokenPunct, closing)
+		return "*"
+	}
+	for p.err == nil {
+		items = append(items, p.parseExpr(precOr).String())
+		if !p.accept(tokenPunct, ",") {
+			break
+		}
+	}
+	p.expect(tokenPunct, closing)
+	return strings.Join(items, ", ")
+}
+
+func (p *exprParser) parseObject() exprNode {
+	var pairs []string
+	if p.accept(tokenPunct, "}") {
+		return exprNode{text: "{}"}
+	}
+	for p.err == nil {
+		key := p.parseExpr(precOr)
+		p.expect(tokenPunct, ":")
+		pairs = append(pairs, fmt.Sprintf("%s: %s", key, p.parseExpr(precOr)))
+		if !p.accept(tokenPunct, ",") {
+			break
+		}
+	}
+	p.expect(tokenPunct, "}")
+	return textNode("{%s}", strings.Join(pairs, ", "))
+}
+
+// parseArray parses the rest of an array comprehension, ARRAY expr FOR bindings [WHEN cond] END.
+func (p *exprParser) parseArray(prefix string) exprNode {
+	mapping := p.parseExpr(precOr)
+	p.expect(tokenKeyword, "for")
+	bindings := p.parseBindings()
+	when := ""
+	if p.accept(tokenKeyword, "when") {
+		when = " when " + p.parseExpr(precOr).String()
+	}
+	p.expect(tokenKeyword, "end")
+	return textNode("(%sarray %s for %s%s end)", prefix, mapping, bindings, when)
+}
+
+// parseBindings parses the variable bindings of a comprehension or collection predicate,
+// [pos :] var IN|WITHIN expr, separated by commas.
+func (p *exprParser) parseBindings() string {
+	var bindings []string
+	for p.err == nil {
+		binding := p.parseVariable()
+		if p.accept(tokenPunct, ":") {
+			binding += ":" + p.parseVariable()
+		}
+		switch {
+		case p.accept(tokenKeyword, "in"):
+			binding += " in "
+		case p.accept(tokenKeyword, "within"):
+			binding += " within "
+		default:
+			p.fail()
+		}
+		bindings = append(bindings, binding+p.parseExpr(precOr).String())
+		if !p.accept(tokenPunct, ",") {
+			break
+		}
+	}
+	return strings.Join(bindings, ", ")
+}
+
+func (p *exprParser) parseVariable() string {
+	t := p.next()
+	if t.kind != tokenIdent {
+		p.unexpected(t)
+		return ""
+	}
+	return QuoteIdentifier(t.text)
+}
+
+// parseCase parses the rest of a simple or searched CASE expression.
+func (p *exprParser) parseCase() exprNode {
+	var b strings.Builder
+	b.WriteString("(case")
+	if t := p.peek(); t.kind != tokenKeyword || t.text != "when" {
+		fmt.Fprintf(&b, " %s", p.parseExpr(precOr))
+	}
+	for p.err == nil && p.accept(tokenKeyword, "when") {
+		cond := p.parseExpr(precOr)
+		p.expect(tokenKeyword, "then")
+		fmt.Fprintf(&b, " when %s then %s", cond, p.parseExpr(precOr))
+	}
+	if p.accept(tokenKeyword, "else") {
+		fmt.Fprintf(&b, " else %s", p.parseExpr(precOr))
+	}
+	p.expect(tokenKeyword, "end")
+	b.WriteString(" end)")
+	return exprNode{text: b.String()}
+}
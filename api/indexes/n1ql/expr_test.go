@@ -0,0 +1,30 @@
+package n1ql
+
+import "testing"
+
+func TestCanonicalExpr(t *testing.T) {
+	tests := map[string]string{
+		"Name":                            "`Name`",
+		"a+b*c":                           "(`a` + (`b` * `c`))",
+		"(a+b)*c":                         "((`a` + `b`) * `c`)",
+		"a + (b + c)":                     "(`a` + `b` + `c`)",
+		"LOWER(name)":                     "lower(`name`)",
+		"type == 'air''line'":             "(`type` = \"air'line\")",
+		"a <> 1 AND NOT b IS NOT NULL":    "((`a` != 1) and (not (`b` is not null)))",
+		"x NOT BETWEEN 1 AND 2 OR y":      "((`x` not between 1 and 2) or `y`)",
+		"s.`End`[0]":                      "`s`.`End`[0]",
+		"name DESC":                       "`name` desc",
+		"name INCLUDE MISSING ASC":        "`name` include missing",
+		"DISTINCT ARRAY v FOR v IN s END": "(distinct array `v` for `v` in `s` end)",
+		"SOME v IN s SATISFIES v > 0 END": "(any `v` in `s` satisfies (`v` > 0) end)",
+		"CASE WHEN a THEN 1 ELSE 2 END":   "(case when `a` then 1 else 2 end)",
+		"":                                "",
+		// Expressions that cannot be parsed keep their tokens, parentheses included.
+		"(a +": "( `a` +",
+	}
+	for expr, want := range tests {
+		if got := CanonicalExpr(expr); got != want {
+			t.Errorf("CanonicalExpr(%q) = %q, want %q", expr, got, want)
+		}
+	}
+}
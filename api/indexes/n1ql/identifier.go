@@ -0,0 +1,64 @@
+// Package n1ql builds the N1QL fragments used in generated index statements.
+package n1ql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// identifierEscaper escapes the characters that are special inside a backtick-quoted identifier.
+// A backtick is doubled, and a backslash is escaped because the query service treats it as the
+// start of an escape sequence.
+var identifierEscaper = strings.NewReplacer("`", "``", `\`, `\\`)
+
+// QuoteIdentifier returns name as a backtick-quoted N1QL identifier. Any name, including one
+// containing backticks, hyphens, spaces or a reserved word, is quoted such that the query
+// service reads it back unchanged.
+func QuoteIdentifier(name string) string {
+	return "`" + identifierEscaper.Replace(name) + "`"
+}
+
+// UnquoteIdentifier reverses QuoteIdentifier. It returns an error if s is not exactly one
+// well-formed backtick-quoted identifier.
+func UnquoteIdentifier(s string) (string, error) {
+	if len(s) < 2 || s[0] != '`' || s[len(s)-1] != '`' {
+		return "", fmt.Errorf("identifier %q is not enclosed in backticks", s)
+	}
+
+	var b strings.Builder
+	body := s[1 : len(s)-1]
+	for i := 0; i < len(body); i++ {
+		switch c := body[i]; c {
+		case '`':
+			if i+1 >= len(body) || body[i+1] != '`' {
+				return "", fmt.Errorf("identifier %q contains an unescaped backtick", s)
+			}
+			b.WriteByte('`')
+			i++
+		case '\\':
+			if i+1 >= len(body) {
+				return "", errors.New("identifier ends with an incomplete escape sequence")
+			}
+			b.WriteByte(body[i+1])
+			i++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), nil
+}
+
+// QuoteIdentifiers quotes each name and joins them with ", ", as in a BUILD INDEX list.
+func QuoteIdentifiers(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = QuoteIdentifier(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// Keyspace returns the quoted `bucket`.`scope`.`collection` path of a collection.
+func Keyspace(bucket, scope, collection string) string {
+	return QuoteIdentifier(bucket) + "." + QuoteIdentifier(scope) + "." + QuoteIdentifier(collection)
+}
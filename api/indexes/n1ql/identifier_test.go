@@ -0,0 +1,69 @@
+package n1ql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQuoteIdentifier(t *testing.T) {
+	tests := map[string]string{
+		"idx":           "`idx`",
+		"travel-sample": "`travel-sample`",
+		"select":        "`select`",
+		"a`b":           "`a``b`",
+		`a\b`:           "`a\\\\b`",
+		"":              "``",
+	}
+	for name, want := range tests {
+		if got := QuoteIdentifier(name); got != want {
+			t.Errorf("QuoteIdentifier(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestUnquoteIdentifier_invalid(t *testing.T) {
+	for _, s := range []string{"", "idx", "`idx", "`a`b`", "`a\\`", "`"} {
+		if got, err := UnquoteIdentifier(s); err == nil {
+			t.Errorf("UnquoteIdentifier(%q) = %q, want an error", s, got)
+		}
+	}
+}
+
+func TestKeyspace(t *testing.T) {
+	got := Keyspace("travel-sample", "in`ventory", "_default")
+	want := "`travel-sample`.`in``ventory`.`_default`"
+	if got != want {
+		t.Fatalf("Keyspace() = %q, want %q", got, want)
+	}
+}
+
+func TestQuoteIdentifiers(t *testing.T) {
+	got := QuoteIdentifiers([]string{"idx1", "idx`2"})
+	want := "`idx1`, `idx``2`"
+	if got != want {
+		t.Fatalf("QuoteIdentifiers() = %q, want %q", got, want)
+	}
+}
+
+// FuzzQuoteIdentifier checks that every name survives a quote/unquote round trip, and that a
+// quoted name cannot terminate the identifier early: the only lone backticks are the enclosing ones.
+func FuzzQuoteIdentifier(f *testing.F) {
+	for _, seed := range []string{"", "idx", "a`b", "``", "`) ; DROP INDEX x; --", `\`, "\\`", "select", "travel-sample", "ü名"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		quoted := QuoteIdentifier(name)
+		got, err := UnquoteIdentifier(quoted)
+		if err != nil {
+			t.Fatalf("UnquoteIdentifier(QuoteIdentifier(%q)) error = %v", name, err)
+		}
+		if got != name {
+			t.Fatalf("round trip of %q = %q", name, got)
+		}
+
+		body := quoted[1 : len(quoted)-1]
+		if strings.Count(strings.ReplaceAll(body, "``", ""), "`") != 0 {
+			t.Fatalf("QuoteIdentifier(%q) = %q contains an unescaped backtick", name, quoted)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package indexes
+
+import (
+	"context"
+
+	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
+)
+
+// DefaultStatusConcurrency bounds how many requests GetIndexBuildStatuses and GetIndexes run at once.
+const DefaultStatusConcurrency = 8
+
+type IndexBuildStatusesRequest struct {
+	OrganizationId string
+	ProjectId      string
+	ClusterId      string
+	Bucket         string
+	IndexNames     []string
+	Scope          string
+	Collection     string
+	// Concurrency bounds the number of parallel requests. Zero means DefaultStatusConcurrency.
+	Concurrency int
+}
+
+// GetIndexBuildStatuses fetches the build status of every index in req.IndexNames in parallel
+// and returns them keyed by index name. Indexes that do not exist are omitted from the result
+// rather than reported as an error. Any other failure cancels the outstanding requests and
+// is returned; the statuses fetched so far are returned alongside it.
+func GetIndexBuildStatuses(ctx context.Context, c *apiclient.Client, req *IndexBuildStatusesRequest) (map[string]string, error) {
+	details, err := GetIndexBuildStatusDetails(ctx, c, req)
+	statuses := make(map[string]string, len(details))
+	for indexName, res := range details {
+		statuses[indexName] = res.Status
+	}
+	return statuses, err
+}
+
+// GetIndexBuildStatusDetails is GetIndexBuildStatuses returning the full build status response
+// of each index rather than only its status.
+func GetIndexBuildStatusDetails(ctx context.Context, c *apiclient.Client, req *IndexBuildStatusesRequest) (map[string]*IndexBuildStatusResponse, error) {
+	return fetchIndexes(ctx, req.IndexNames, req.Concurrency, "build status", func(ctx context.Context, indexName string) (*IndexBuildStatusResponse, error) {
+		return GetIndexBuildStatus(ctx, c, &IndexBuildStatusRequest{
+			OrganizationId: req.OrganizationId,
+			ProjectId:      req.ProjectId,
+			ClusterId:      req.ClusterId,
+			Bucket:         req.Bucket,
+			IndexName:      indexName,
+			Scope:          req.Scope,
+			Collection:     req.Collection,
+		})
+	})
+}
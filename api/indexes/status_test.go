@@ -0,0 +1,153 @@
+package indexes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetIndexBuildStatuses(t *testing.T) {
+	ts := statusSequenceServer(t, map[string][]string{
+		"idx1": {"Ready"},
+		"idx2": {"Created"},
+	})
+	defer ts.Close()
+
+	statuses, err := GetIndexBuildStatuses(context.Background(), newTestClient(ts.URL), &IndexBuildStatusesRequest{
+		IndexNames: []string{"idx1", "idx2", "missing"},
+	})
+	if err != nil {
+		t.Fatalf("GetIndexBuildStatuses() error = %v", err)
+	}
+	want := map[string]string{"idx1": "Ready", "idx2": "Created"}
+	if fmt.Sprint(statuses) != fmt.Sprint(want) {
+		t.Fatalf("statuses = %v, want %v", statuses, want)
+	}
+}
+
+func TestGetIndexBuildStatus_UnknownFields(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"Building","progress":40,"lastScanTime":"2026-01-01T00:00:00Z"}`))
+	}))
+	defer ts.Close()
+
+	res, err := GetIndexBuildStatus(context.Background(), newTestClient(ts.URL), &IndexBuildStatusRequest{IndexName: "idx1"})
+	if err != nil {
+		t.Fatalf("GetIndexBuildStatus() error = %v", err)
+	}
+	if res.Status != "Building" || res.Progress == nil || *res.Progress != 40 {
+		t.Fatalf("GetIndexBuildStatus() = %+v, want status Building with progress 40", res)
+	}
+}
+
+func TestGetIndexBuildStatus_EmptyBody(t *testing.T) {
+	for _, body := range []string{"", "null"} {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(body))
+		}))
+
+		res, err := GetIndexBuildStatus(context.Background(), newTestClient(ts.URL), &IndexBuildStatusRequest{IndexName: "idx1"})
+		ts.Close()
+		if err != nil {
+			t.Fatalf("GetIndexBuildStatus() with body %q error = %v", body, err)
+		}
+		if res == nil || res.Status != "" {
+			t.Fatalf("GetIndexBuildStatus() with body %q = %+v, want an empty response", body, res)
+		}
+	}
+}
+
+func TestGetIndexBuildStatuses_Error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/broken") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"status": "Ready"})
+	}))
+	defer ts.Close()
+
+	_, err := GetIndexBuildStatuses(context.Background(), newTestClient(ts.URL), &IndexBuildStatusesRequest{
+		IndexNames: []string{"idx1", "broken", "idx2"},
+	})
+	if err == nil || !strings.Contains(err.Error(), `"broken"`) {
+		t.Fatalf("GetIndexBuildStatuses() error = %v, want an error naming the broken index", err)
+	}
+}
+
+func TestGetIndexBuildStatuses_BoundedConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+		time.Sleep(10 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"status": "Ready"})
+	}))
+	defer ts.Close()
+
+	names := make([]string, 20)
+	for i := range names {
+		names[i] = fmt.Sprintf("idx%d", i)
+	}
+	statuses, err := GetIndexBuildStatuses(context.Background(), newTestClient(ts.URL), &IndexBuildStatusesRequest{
+		IndexNames:  names,
+		Concurrency: 3,
+	})
+	if err != nil {
+		t.Fatalf("GetIndexBuildStatuses() error = %v", err)
+	}
+	if len(statuses) != len(names) {
+		t.Fatalf("got %d statuses, want %d", len(statuses), len(names))
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if peak > 3 {
+		t.Fatalf("peak concurrent requests = %d, want at most 3", peak)
+	}
+}
+
+func TestGetIndexBuildStatusDetails(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/idx1") {
+			_, _ = w.Write([]byte(`{"status":"Building","progress":40,"numReplica":1,"hosts":["node1:9102","node2:9102"]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"Ready"}`))
+	}))
+	defer ts.Close()
+
+	details, err := GetIndexBuildStatusDetails(context.Background(), newTestClient(ts.URL), &IndexBuildStatusesRequest{
+		IndexNames: []string{"idx1", "idx2"},
+	})
+	if err != nil {
+		t.Fatalf("GetIndexBuildStatusDetails() error = %v", err)
+	}
+	idx1 := details["idx1"]
+	if idx1 == nil || idx1.Status != "Building" || idx1.Progress == nil || *idx1.Progress != 40 ||
+		idx1.NumReplica == nil || *idx1.NumReplica != 1 || strings.Join(idx1.Hosts, ",") != "node1:9102,node2:9102" {
+		t.Fatalf("details[idx1] = %+v, want status, progress, replicas and hosts", idx1)
+	}
+	idx2 := details["idx2"]
+	if idx2 == nil || idx2.Status != "Ready" || idx2.Progress != nil || idx2.NumReplica != nil || idx2.Hosts != nil {
+		t.Fatalf("details[idx2] = %+v, want only the status", idx2)
+	}
+}
@@ -0,0 +1,149 @@
+package indexes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
+)
+
+// DefaultReadyStatuses are the statuses WaitForIndexBuild treats as a finished build.
+var DefaultReadyStatuses = []string{"Ready"}
+
+// DefaultFailureStatuses are the statuses WaitForIndexBuild treats as a failed build.
+var DefaultFailureStatuses = []string{"Error"}
+
+// Polling starts quickly so small builds return promptly, then backs off so long
+// builds do not hammer the API.
+var (
+	waitPollIntervalMin = 1 * time.Second
+	waitPollIntervalMax = 15 * time.Second
+)
+
+type WaitForIndexBuildRequest struct {
+	OrganizationId  string
+	ProjectId       string
+	ClusterId       string
+	Bucket          string
+	IndexNames      []string
+	Scope           string
+	Collection      string
+	ReadyStatuses   []string
+	FailureStatuses []string
+	// OnStatusChange, if set, is called each time an index is observed in a new status or with
+	// new build progress.
+	OnStatusChange func(indexName string, res *IndexBuildStatusResponse)
+}
+
+// IndexBuildFailedError is returned by WaitForIndexBuild when an index reaches a failure status.
+type IndexBuildFailedError struct {
+	IndexName string
+	Status    string
+}
+
+func (e *IndexBuildFailedError) Error() string {
+	return fmt.Sprintf("index %q entered status %q", e.IndexName, e.Status)
+}
+
+// WaitForIndexBuild polls the build status of the pending indexes, fetched concurrently by
+// GetIndexBuildStatusDetails, until every index in req.IndexNames reaches one of
+// req.ReadyStatuses. It returns early with an *IndexBuildFailedError if any index reaches one of
+// req.FailureStatuses, or with the context error once ctx is done. The last observed status of
+// every index is always returned, even on error.
+func WaitForIndexBuild(ctx context.Context, c *apiclient.Client, req *WaitForIndexBuildRequest) (map[string]string, error) {
+	readyStatuses := req.ReadyStatuses
+	if len(readyStatuses) == 0 {
+		readyStatuses = DefaultReadyStatuses
+	}
+	failureStatuses := req.FailureStatuses
+	if len(failureStatuses) == 0 {
+		failureStatuses = DefaultFailureStatuses
+	}
+	readySet := toSet(readyStatuses)
+	failureSet := toSet(failureStatuses)
+
+	statuses := make(map[string]string, len(req.IndexNames))
+	progress := make(map[string]int64, len(req.IndexNames))
+	pending := toSet(req.IndexNames)
+	interval := waitPollIntervalMin
+
+	for {
+		names := make([]string, 0, len(pending))
+		for _, indexName := range req.IndexNames {
+			if pending[indexName] {
+				names = append(names, indexName)
+			}
+		}
+		details, err := GetIndexBuildStatusDetails(ctx, c, &IndexBuildStatusesRequest{
+			OrganizationId: req.OrganizationId,
+			ProjectId:      req.ProjectId,
+			ClusterId:      req.ClusterId,
+			Bucket:         req.Bucket,
+			IndexNames:     names,
+			Scope:          req.Scope,
+			Collection:     req.Collection,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return statuses, waitTimeoutError(ctx, pending)
+			}
+			return statuses, err
+		}
+
+		for _, indexName := range names {
+			res, ok := details[indexName]
+			if !ok {
+				return statuses, fmt.Errorf("cannot get build status for index %q: index not found", indexName)
+			}
+			current := int64(-1)
+			if res.Progress != nil {
+				current = *res.Progress
+			}
+			prev, seen := statuses[indexName]
+			if (!seen || prev != res.Status || progress[indexName] != current) && req.OnStatusChange != nil {
+				req.OnStatusChange(indexName, res)
+			}
+			statuses[indexName] = res.Status
+			progress[indexName] = current
+
+			if failureSet[res.Status] {
+				return statuses, &IndexBuildFailedError{IndexName: indexName, Status: res.Status}
+			}
+			if readySet[res.Status] {
+				delete(pending, indexName)
+			}
+		}
+
+		if len(pending) == 0 {
+			return statuses, nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return statuses, waitTimeoutError(ctx, pending)
+		case <-timer.C:
+		}
+		interval = min(interval*2, waitPollIntervalMax)
+	}
+}
+
+func waitTimeoutError(ctx context.Context, pending map[string]bool) error {
+	names := make([]string, 0, len(pending))
+	for name := range pending {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("indexes %v did not become ready: %w", names, ctx.Err())
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
@@ -0,0 +1,209 @@
+package indexes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+)
+
+// statusSequenceServer returns successive statuses from seq for each index on every GET,
+// repeating the last one once the sequence is exhausted.
+func statusSequenceServer(t *testing.T, seq map[string][]string) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		statuses, ok := seq[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		i := min(calls[name], len(statuses)-1)
+		calls[name]++
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"status": statuses[i]})
+	}))
+}
+
+func newTestClient(url string) *apiclient.Client {
+	rhc := retryablehttp.NewClient()
+	rhc.RetryMax = 0
+	return apiclient.NewClient(apiclient.WithBaseURL(url), apiclient.WithHTTPClient(rhc))
+}
+
+func fastPolling(t *testing.T) {
+	t.Helper()
+	prevMin, prevMax := waitPollIntervalMin, waitPollIntervalMax
+	waitPollIntervalMin, waitPollIntervalMax = time.Millisecond, 5*time.Millisecond
+	t.Cleanup(func() { waitPollIntervalMin, waitPollIntervalMax = prevMin, prevMax })
+}
+
+func TestWaitForIndexBuild_AllReady(t *testing.T) {
+	fastPolling(t)
+	ts := statusSequenceServer(t, map[string][]string{
+		"idx1": {"Building", "Ready"},
+		"idx2": {"Building", "Building", "Ready"},
+	})
+	defer ts.Close()
+
+	var changes []string
+	statuses, err := WaitForIndexBuild(context.Background(), newTestClient(ts.URL), &WaitForIndexBuildRequest{
+		IndexNames: []string{"idx1", "idx2"},
+		OnStatusChange: func(indexName string, res *IndexBuildStatusResponse) {
+			changes = append(changes, indexName+"="+res.Status)
+		},
+	})
+	if err != nil {
+		t.Fatalf("WaitForIndexBuild() error = %v", err)
+	}
+	if statuses["idx1"] != "Ready" || statuses["idx2"] != "Ready" {
+		t.Fatalf("statuses = %v, want all Ready", statuses)
+	}
+	want := []string{"idx1=Building", "idx2=Building", "idx1=Ready", "idx2=Ready"}
+	if strings.Join(changes, ",") != strings.Join(want, ",") {
+		t.Fatalf("status changes = %v, want %v", changes, want)
+	}
+}
+
+func TestWaitForIndexBuild_ProgressChange(t *testing.T) {
+	fastPolling(t)
+	bodies := []string{
+		`{"status":"Building","progress":10}`,
+		`{"status":"Building","progress":10}`,
+		`{"status":"Building","progress":60}`,
+		`{"status":"Ready"}`,
+	}
+	var mu sync.Mutex
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		body := bodies[min(calls, len(bodies)-1)]
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer ts.Close()
+
+	var changes []string
+	_, err := WaitForIndexBuild(context.Background(), newTestClient(ts.URL), &WaitForIndexBuildRequest{
+		IndexNames: []string{"idx1"},
+		OnStatusChange: func(indexName string, res *IndexBuildStatusResponse) {
+			change := indexName + "=" + res.Status
+			if res.Progress != nil {
+				change += fmt.Sprintf("@%d", *res.Progress)
+			}
+			changes = append(changes, change)
+		},
+	})
+	if err != nil {
+		t.Fatalf("WaitForIndexBuild() error = %v", err)
+	}
+	want := []string{"idx1=Building@10", "idx1=Building@60", "idx1=Ready"}
+	if strings.Join(changes, ",") != strings.Join(want, ",") {
+		t.Fatalf("status changes = %v, want %v", changes, want)
+	}
+}
+
+func TestWaitForIndexBuild_FailureStatus(t *testing.T) {
+	fastPolling(t)
+	ts := statusSequenceServer(t, map[string][]string{
+		"idx1": {"Building", "Error"},
+	})
+	defer ts.Close()
+
+	statuses, err := WaitForIndexBuild(context.Background(), newTestClient(ts.URL), &WaitForIndexBuildRequest{
+		IndexNames: []string{"idx1"},
+	})
+	var failed *IndexBuildFailedError
+	if !errors.As(err, &failed) {
+		t.Fatalf("WaitForIndexBuild() error = %v, want *IndexBuildFailedError", err)
+	}
+	if failed.IndexName != "idx1" || failed.Status != "Error" || statuses["idx1"] != "Error" {
+		t.Fatalf("unexpected failure %+v, statuses %v", failed, statuses)
+	}
+}
+
+func TestWaitForIndexBuild_Timeout(t *testing.T) {
+	fastPolling(t)
+	ts := statusSequenceServer(t, map[string][]string{
+		"idx1": {"Building"},
+	})
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	statuses, err := WaitForIndexBuild(ctx, newTestClient(ts.URL), &WaitForIndexBuildRequest{
+		IndexNames: []string{"idx1"},
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitForIndexBuild() error = %v, want context.DeadlineExceeded", err)
+	}
+	if statuses["idx1"] != "Building" {
+		t.Fatalf("statuses = %v, want idx1 Building", statuses)
+	}
+}
+
+func TestWaitForIndexBuild_ConcurrentPolls(t *testing.T) {
+	fastPolling(t)
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"status": "Ready"})
+	}))
+	defer ts.Close()
+
+	statuses, err := WaitForIndexBuild(context.Background(), newTestClient(ts.URL), &WaitForIndexBuildRequest{
+		IndexNames: []string{"idx1", "idx2", "idx3"},
+	})
+	if err != nil {
+		t.Fatalf("WaitForIndexBuild() error = %v", err)
+	}
+	if len(statuses) != 3 {
+		t.Fatalf("statuses = %v, want 3 Ready", statuses)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if peak < 2 {
+		t.Fatalf("peak concurrent requests = %d, want the indexes polled concurrently", peak)
+	}
+}
+
+func TestWaitForIndexBuild_MissingIndex(t *testing.T) {
+	fastPolling(t)
+	ts := statusSequenceServer(t, map[string][]string{
+		"idx1": {"Building"},
+	})
+	defer ts.Close()
+
+	_, err := WaitForIndexBuild(context.Background(), newTestClient(ts.URL), &WaitForIndexBuildRequest{
+		IndexNames: []string{"idx1", "missing"},
+	})
+	if err == nil || !strings.Contains(err.Error(), `"missing"`) {
+		t.Fatalf("WaitForIndexBuild() error = %v, want an error naming the missing index", err)
+	}
+}
@@ -0,0 +1,17 @@
+data "capellaextras_index_build_status" "by_airline" {
+  organization_id = local.org_id
+  project_id      = couchbase-capella_project.new_project.id
+  cluster_id      = couchbase-capella_free_tier_cluster.new_free_tier_cluster.id
+  bucket_name     = couchbase-capella_bucket.new_free_tier_bucket.name
+  scope_name      = "inventory"
+  collection_name = "route"
+  index_name      = "idx_by_airline"
+}
+
+# Warn on every plan while the index is not yet usable.
+check "idx_by_airline_ready" {
+  assert {
+    condition     = data.capellaextras_index_build_status.by_airline.status == "Ready"
+    error_message = "Index idx_by_airline is ${data.capellaextras_index_build_status.by_airline.status}, not Ready."
+  }
+}
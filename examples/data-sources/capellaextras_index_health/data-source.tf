@@ -0,0 +1,31 @@
+# Count the indexes of every keyspace in the cluster.
+data "capellaextras_index_health" "cluster" {
+  organization_id = local.org_id
+  project_id      = couchbase-capella_project.new_project.id
+  cluster_id      = couchbase-capella_free_tier_cluster.new_free_tier_cluster.id
+}
+
+# Warn on every plan while any index is stuck deferred, failed or offline.
+check "cluster_indexes_healthy" {
+  assert {
+    condition     = data.capellaextras_index_health.cluster.all_ready
+    error_message = "Indexes not Ready: ${jsonencode(data.capellaextras_index_health.cluster.not_ready_indexes)}"
+  }
+}
+
+# Or limit the counts to a single keyspace.
+data "capellaextras_index_health" "route" {
+  organization_id = local.org_id
+  project_id      = couchbase-capella_project.new_project.id
+  cluster_id      = couchbase-capella_free_tier_cluster.new_free_tier_cluster.id
+  bucket_name     = couchbase-capella_bucket.new_free_tier_bucket.name
+  scope_name      = "inventory"
+  collection_name = "route"
+}
+
+check "route_indexes_built" {
+  assert {
+    condition     = data.capellaextras_index_health.route.deferred == 0
+    error_message = "${data.capellaextras_index_health.route.deferred} deferred indexes in inventory.route have not been built."
+  }
+}
@@ -0,0 +1,16 @@
+data "capellaextras_indexes" "route" {
+  organization_id = local.org_id
+  project_id      = couchbase-capella_project.new_project.id
+  cluster_id      = couchbase-capella_free_tier_cluster.new_free_tier_cluster.id
+  bucket_name     = couchbase-capella_bucket.new_free_tier_bucket.name
+  scope_name      = "inventory"
+  collection_name = "route"
+}
+
+# Names of the indexes that were created deferred and are still waiting to be built.
+output "unbuilt_indexes" {
+  value = [
+    for idx in data.capellaextras_indexes.route.indexes : idx.name
+    if idx.deferred && idx.state == "Created"
+  ]
+}
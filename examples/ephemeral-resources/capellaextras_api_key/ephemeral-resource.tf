@@ -0,0 +1,17 @@
+# A project-scoped API key that only exists while Terraform runs.
+ephemeral "capellaextras_api_key" "app" {
+  organization_id = local.org_id
+  project_id      = couchbase-capella_project.new_project.id
+  name_prefix     = "app-deploy-"
+  description     = "Short-lived key for the app module"
+  project_roles   = ["projectDataReaderWriter"]
+  allowed_cidrs   = ["10.0.0.0/8"]
+  expiry          = 0.25
+}
+
+# Hand the key to a provider configuration instead of the organization-wide token. Provider
+# configurations can reference ephemeral values.
+provider "couchbase-capella" {
+  alias                = "app"
+  authentication_token = ephemeral.capellaextras_api_key.app.token
+}
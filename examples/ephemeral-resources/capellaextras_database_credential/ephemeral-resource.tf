@@ -0,0 +1,28 @@
+# A credential that only exists while Terraform runs, e.g. to seed a collection from CI.
+ephemeral "capellaextras_database_credential" "seed" {
+  organization_id = local.org_id
+  project_id      = couchbase-capella_project.new_project.id
+  cluster_id      = couchbase-capella_free_tier_cluster.new_free_tier_cluster.id
+  name_prefix     = "seed-"
+
+  access = [
+    {
+      privileges  = ["data_reader", "data_writer"]
+      bucket_name = couchbase-capella_bucket.new_free_tier_bucket.name
+      scope_name  = "inventory"
+    },
+  ]
+}
+
+# Ephemeral values can be used in provisioners, which never store them in state.
+resource "terraform_data" "seed" {
+  triggers_replace = [couchbase-capella_bucket.new_free_tier_bucket.name]
+
+  provisioner "local-exec" {
+    command = "./seed.sh"
+    environment = {
+      CB_USERNAME = ephemeral.capellaextras_database_credential.seed.username
+      CB_PASSWORD = ephemeral.capellaextras_database_credential.seed.password
+    }
+  }
+}
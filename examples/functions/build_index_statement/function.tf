@@ -0,0 +1,14 @@
+locals {
+  route_indexes = ["idx_by_airline", "idx_by_source"]
+}
+
+# Render the statement that builds the deferred route indexes, e.g. for a runbook or a
+# script run outside Terraform.
+output "build_route_indexes" {
+  value = provider::capellaextras::build_index_statement(
+    couchbase-capella_bucket.new_free_tier_bucket.name,
+    "inventory",
+    "route",
+    local.route_indexes,
+  )
+}
@@ -0,0 +1,21 @@
+output "create_idx_by_airline" {
+  value = provider::capellaextras::create_index_statement(
+    couchbase-capella_bucket.new_free_tier_bucket.name,
+    "inventory",
+    "route",
+    "idx_by_airline",
+    ["airline", "sourceairport"],
+    {
+      where       = "stops = 0"
+      defer_build = true
+      num_replica = 1
+    },
+  )
+}
+
+# A primary index takes no keys.
+output "create_primary" {
+  value = provider::capellaextras::create_index_statement(
+    couchbase-capella_bucket.new_free_tier_bucket.name, null, null, "#primary", null, { is_primary = true },
+  )
+}
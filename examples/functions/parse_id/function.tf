@@ -0,0 +1,26 @@
+# Build the deferred indexes of a keyspace identified by an ID of this provider...
+locals {
+  keyspace = provider::capellaextras::parse_id(capellaextras_deferred_index_build.route.id)
+}
+
+output "route_keyspace" {
+  value = "${local.keyspace.bucket_name}.${local.keyspace.scope_name}.${local.keyspace.collection_name}"
+}
+
+# ...or wire capellaextras_deferred_index_build from the composite import ID of an upstream
+# Capella bucket. The bucket_id is decoded into the bucket name.
+locals {
+  bucket = provider::capellaextras::parse_id(
+    "bucket_id=${couchbase-capella_bucket.new_free_tier_bucket.id},cluster_id=${couchbase-capella_free_tier_cluster.new_free_tier_cluster.id},project_id=${couchbase-capella_project.new_project.id},organization_id=${local.org_id}"
+  )
+}
+
+resource "capellaextras_deferred_index_build" "airline" {
+  organization_id = local.bucket.organization_id
+  project_id      = local.bucket.project_id
+  cluster_id      = local.bucket.cluster_id
+  bucket_name     = local.bucket.bucket_name
+  scope_name      = "inventory"
+  collection_name = "airline"
+  index_names     = ["idx_by_name"]
+}
@@ -0,0 +1,4 @@
+# Import ID format: {organization_id}/{project_id}/{cluster_id}/{bucket_name}/{scope_name}/{collection_name}/{index_names}
+# index_names is comma separated. Use _default for the default scope or collection.
+terraform import capellaextras_deferred_index_build.indexes \
+  aaaaaaaa-8f0c-22222-865e-bbbbbbbbbbbb/my-project-id/my-cluster-id/my-bucket/_default/_default/idx1,idx2,idx3
@@ -0,0 +1,47 @@
+locals {
+  org_id = "aaaaaaaa-8f0c-22222-865e-bbbbbbbbbbbb"
+  indexes = {
+    route   = { idx_route_src = ["sourceairport"], idx_route_dst = ["destinationairport"] }
+    airline = { idx_airline_name = ["name"] }
+    hotel   = { idx_hotel_city = ["city"], idx_hotel_country = ["country"] }
+  }
+  index_list = flatten([
+    for collection, idxs in local.indexes : [
+      for name, keys in idxs : { collection = collection, name = name, keys = keys }
+    ]
+  ])
+}
+
+resource "couchbase-capella_query_indexes" "index" {
+  for_each        = { for idx in local.index_list : "${idx.collection}.${idx.name}" => idx }
+  organization_id = local.org_id
+  project_id      = couchbase-capella_project.new_project.id
+  cluster_id      = couchbase-capella_cluster.new_cluster.id
+  bucket_name     = "travel-sample"
+  scope_name      = "inventory"
+  collection_name = each.value.collection
+  index_name      = each.value.name
+  index_keys      = each.value.keys
+  with = {
+    defer_build = true
+  }
+}
+
+# One resource builds the deferred indexes of every collection, one BUILD INDEX
+# statement per keyspace.
+resource "capellaextras_deferred_index_builds" "inventory" {
+  organization_id = local.org_id
+  project_id      = couchbase-capella_project.new_project.id
+  cluster_id      = couchbase-capella_cluster.new_cluster.id
+
+  keyspaces = [
+    for collection, idxs in local.indexes : {
+      bucket_name     = "travel-sample"
+      scope_name      = "inventory"
+      collection_name = collection
+      index_names     = keys(idxs)
+    }
+  ]
+
+  depends_on = [couchbase-capella_query_indexes.index]
+}
@@ -0,0 +1,4 @@
+# Import ID format: {organization_id}/{project_id}/{cluster_id}/{bucket_name}/{scope_name}/{collection_name}/{index_name}
+# Use _default for the default scope or collection.
+terraform import capellaextras_query_index.by_airline \
+  aaaaaaaa-8f0c-22222-865e-bbbbbbbbbbbb/my-project-id/my-cluster-id/my-bucket/inventory/route/idx_by_airline
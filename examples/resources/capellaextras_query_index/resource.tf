@@ -0,0 +1,39 @@
+resource "capellaextras_query_index" "by_airline" {
+  organization_id = local.org_id
+  project_id      = couchbase-capella_project.new_project.id
+  cluster_id      = couchbase-capella_free_tier_cluster.new_free_tier_cluster.id
+  bucket_name     = couchbase-capella_bucket.new_free_tier_bucket.name
+  scope_name      = "inventory"
+  collection_name = "route"
+  index_name      = "idx_by_airline"
+
+  index_keys = ["airline", "DISTINCT ARRAY s.day FOR s IN schedule END"]
+  where      = "stops = 0"
+
+  with = {
+    defer_build = true
+    num_replica = 1
+  }
+}
+
+resource "capellaextras_query_index" "primary" {
+  organization_id = local.org_id
+  project_id      = couchbase-capella_project.new_project.id
+  cluster_id      = couchbase-capella_free_tier_cluster.new_free_tier_cluster.id
+  bucket_name     = couchbase-capella_bucket.new_free_tier_bucket.name
+  scope_name      = "inventory"
+  collection_name = "route"
+  index_name      = "#primary"
+  is_primary      = true
+}
+
+# Build the deferred index once it has been created.
+resource "capellaextras_deferred_index_build" "route" {
+  organization_id = local.org_id
+  project_id      = couchbase-capella_project.new_project.id
+  cluster_id      = couchbase-capella_free_tier_cluster.new_free_tier_cluster.id
+  bucket_name     = couchbase-capella_bucket.new_free_tier_bucket.name
+  scope_name      = "inventory"
+  collection_name = "route"
+  index_names     = [capellaextras_query_index.by_airline.index_name]
+}
@@ -6,17 +6,23 @@ package actions
 import (
 	"context"
 	"fmt"
+	"time"
 
 	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
 	"github.com/cdsre/terraform-provider-capellaextras/api/indexes"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/indexwait"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/keyspace"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/providerdefaults"
 	"github.com/hashicorp/terraform-plugin-framework/action"
 	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ action.Action = &BuildIndexAction{}
 var _ action.ActionWithConfigure = &BuildIndexAction{}
+var _ action.ActionWithValidateConfig = &BuildIndexAction{}
 
 func NewBuildIndexAction() action.Action {
 	return &BuildIndexAction{}
@@ -29,13 +35,16 @@ type BuildIndexAction struct {
 
 // BuildIndexActionModel describes the action data model.
 type BuildIndexActionModel struct {
-	OrganizationId types.String `tfsdk:"organization_id"`
-	ProjectId      types.String `tfsdk:"project_id"`
-	ClusterId      types.String `tfsdk:"cluster_id"`
-	BucketName     types.String `tfsdk:"bucket_name"`
-	IndexNames     types.List   `tfsdk:"index_names"`
-	ScopeName      types.String `tfsdk:"scope_name"`
-	CollectionName types.String `tfsdk:"collection_name"`
+	OrganizationId     types.String `tfsdk:"organization_id"`
+	ProjectId          types.String `tfsdk:"project_id"`
+	ClusterId          types.String `tfsdk:"cluster_id"`
+	BucketName         types.String `tfsdk:"bucket_name"`
+	IndexNames         types.List   `tfsdk:"index_names"`
+	ScopeName          types.String `tfsdk:"scope_name"`
+	CollectionName     types.String `tfsdk:"collection_name"`
+	Wait               types.Bool   `tfsdk:"wait"`
+	Timeout            types.String `tfsdk:"timeout"`
+	MaxIndexesPerBuild types.Int64  `tfsdk:"max_indexes_per_build"`
 }
 
 func (bi *BuildIndexAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
@@ -49,12 +58,12 @@ func (bi *BuildIndexAction) Schema(ctx context.Context, req action.SchemaRequest
 
 		Attributes: map[string]schema.Attribute{
 			"organization_id": schema.StringAttribute{
-				MarkdownDescription: "The organization id where the index is located.",
-				Required:            true,
+				MarkdownDescription: "The organization id where the index is located. Defaults to the provider `organization_id`.",
+				Optional:            true,
 			},
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "The project id where the index is located.",
-				Required:            true,
+				MarkdownDescription: "The project id where the index is located. Defaults to the provider `project_id`.",
+				Optional:            true,
 			},
 			"cluster_id": schema.StringAttribute{
 				MarkdownDescription: "The cluster id where the index is located.",
@@ -77,10 +86,48 @@ func (bi *BuildIndexAction) Schema(ctx context.Context, req action.SchemaRequest
 				MarkdownDescription: "The name of the scope where the index is located.",
 				Optional:            true,
 			},
+			"wait": schema.BoolAttribute{
+				MarkdownDescription: "Wait for the triggered indexes to become `Ready`, reporting each status change as it happens. " +
+					"The action fails if an index enters the `Error` status or the timeout elapses.",
+				Optional: true,
+			},
+			"timeout": schema.StringAttribute{
+				MarkdownDescription: "How long to wait for the builds when `wait` is `true`, as a Go duration string " +
+					"(e.g. `30m`, `1h`). Defaults to `" + indexwait.DefaultTimeout + "`.",
+				Optional: true,
+			},
+			"max_indexes_per_build": schema.Int64Attribute{
+				MarkdownDescription: "Split the indexes to build into `BUILD INDEX` statements of at most this many indexes, " +
+					"submitted one after another. A failed statement does not stop the remaining ones. " +
+					"By default all indexes are built in a single statement.",
+				Optional: true,
+			},
 		},
 	}
 }
 
+func (bi *BuildIndexAction) ValidateConfig(ctx context.Context, req action.ValidateConfigRequest, resp *action.ValidateConfigResponse) {
+	var timeout types.String
+	var maxPerBuild types.Int64
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("timeout"), &timeout)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("max_indexes_per_build"), &maxPerBuild)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !timeout.IsNull() && !timeout.IsUnknown() {
+		indexwait.ParseTimeout(timeout, &resp.Diagnostics)
+	}
+
+	if !maxPerBuild.IsNull() && !maxPerBuild.IsUnknown() && maxPerBuild.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_indexes_per_build"),
+			"Invalid Max Indexes Per Build",
+			fmt.Sprintf("max_indexes_per_build must be at least 1, got %d.", maxPerBuild.ValueInt64()),
+		)
+	}
+}
+
 func (bi *BuildIndexAction) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
@@ -110,19 +157,14 @@ func (bi *BuildIndexAction) Invoke(ctx context.Context, req action.InvokeRequest
 		return
 	}
 
-	// Set default values for optional attributes
-	var scope, collection string
-	if data.ScopeName.IsNull() {
-		scope = "_default"
-	} else {
-		scope = data.ScopeName.ValueString()
-	}
-	if data.CollectionName.IsNull() {
-		collection = "_default"
-	} else {
-		collection = data.CollectionName.ValueString()
+	data.OrganizationId, data.ProjectId = providerdefaults.ResolveOrgProject(bi.Client, "action", data.OrganizationId, data.ProjectId, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
+	// Set default values for optional attributes
+	scope, collection := keyspace.NameOrDefault(data.ScopeName), keyspace.NameOrDefault(data.CollectionName)
+
 	var indexNames []string
 	diags := data.IndexNames.ElementsAs(ctx, &indexNames, false)
 	resp.Diagnostics.Append(diags...)
@@ -130,31 +172,40 @@ func (bi *BuildIndexAction) Invoke(ctx context.Context, req action.InvokeRequest
 		return
 	}
 
+	statuses, err := indexes.GetIndexBuildStatuses(ctx, bi.Client, &indexes.IndexBuildStatusesRequest{
+		OrganizationId: data.OrganizationId.ValueString(),
+		ProjectId:      data.ProjectId.ValueString(),
+		ClusterId:      data.ClusterId.ValueString(),
+		Bucket:         data.BucketName.ValueString(),
+		IndexNames:     indexNames,
+		Scope:          scope,
+		Collection:     collection,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Get Index Build Status Failed",
+			fmt.Sprintf("Cannot get index build statuses.  Error: %v\n", err.Error()),
+		)
+		return
+	}
+
 	var buildIndexes []string
 	for _, indexName := range indexNames {
-		res, err := indexes.GetIndexBuildStatus(ctx, bi.Client, &indexes.IndexBuildStatusRequest{
-			OrganizationId: data.OrganizationId.ValueString(),
-			ProjectId:      data.ProjectId.ValueString(),
-			ClusterId:      data.ClusterId.ValueString(),
-			Bucket:         data.BucketName.ValueString(),
-			IndexName:      indexName,
-			Scope:          scope,
-			Collection:     collection,
-		})
-		if err != nil {
+		status, ok := statuses[indexName]
+		if !ok {
 			resp.Diagnostics.AddError(
 				"Get Index Build Status Failed",
-				fmt.Sprintf("Cannot get index build status for index %s.  Error: %v\n", indexName, err.Error()),
+				fmt.Sprintf("Cannot get index build status for index %s.  Error: index does not exist\n", indexName),
 			)
 			return
 		}
 
 		// Send a progress message back to Terraform
 		resp.SendProgress(action.InvokeProgressEvent{
-			Message: fmt.Sprintf("Index: %s, Status: %s", indexName, res.Status),
+			Message: fmt.Sprintf("Index: %s, Status: %s", indexName, status),
 		})
 
-		if res.Status == "Created" {
+		if status == "Created" {
 			buildIndexes = append(buildIndexes, indexName)
 		}
 	}
@@ -172,26 +223,90 @@ func (bi *BuildIndexAction) Invoke(ctx context.Context, req action.InvokeRequest
 		Message: fmt.Sprintf("The following indexes need built: %v", buildIndexes),
 	})
 
-	_, err := indexes.BuildDeferredIndexes(ctx, bi.Client, &indexes.IndexBuildRequest{
+	res, err := indexes.BuildDeferredIndexes(ctx, bi.Client, &indexes.IndexBuildRequest{
+		OrganizationId:     data.OrganizationId.ValueString(),
+		ProjectId:          data.ProjectId.ValueString(),
+		ClusterId:          data.ClusterId.ValueString(),
+		Bucket:             data.BucketName.ValueString(),
+		Collection:         collection,
+		Scope:              scope,
+		IndexNames:         buildIndexes,
+		MaxIndexesPerBuild: int(data.MaxIndexesPerBuild.ValueInt64()),
+	})
+	if len(res.Built) > 0 && len(res.Failed) > 0 {
+		// Send a progress message back to Terraform
+		resp.SendProgress(action.InvokeProgressEvent{
+			Message: fmt.Sprintf("Builds were started for: %v", res.Built),
+		})
+	}
+	// Wait for the builds that started before reporting the batches that failed, so a failed batch
+	// does not leave the others unobserved.
+	var ready bool
+	var elapsed time.Duration
+	if data.Wait.ValueBool() && len(res.Built) > 0 {
+		elapsed, ready = bi.waitForBuild(ctx, &data, scope, collection, res.Built, resp)
+	}
+
+	if err != nil {
+		for _, failed := range res.Failed {
+			resp.Diagnostics.AddError(
+				"Build Deferred Indexes Failed",
+				fmt.Sprintf("Cannot build deferred indexes %v.  Error: %v\n", failed.IndexNames, failed.Err.Error()),
+			)
+		}
+		return
+	}
+
+	switch {
+	case !data.Wait.ValueBool():
+		// Send a progress message back to Terraform
+		resp.SendProgress(action.InvokeProgressEvent{
+			Message: "finished action invocation, Indexes will be built in the background.",
+		})
+	case ready:
+		// Send a progress message back to Terraform
+		resp.SendProgress(action.InvokeProgressEvent{
+			Message: fmt.Sprintf("finished action invocation, all indexes built in %s.", elapsed.Round(time.Second)),
+		})
+	}
+}
+
+// waitForBuild waits for indexNames to become ready, reporting each status change as progress. It
+// reports whether they did, and how long the wait took.
+func (bi *BuildIndexAction) waitForBuild(ctx context.Context, data *BuildIndexActionModel, scope, collection string, indexNames []string, resp *action.InvokeResponse) (time.Duration, bool) {
+	if data.Timeout.IsNull() {
+		data.Timeout = types.StringValue(indexwait.DefaultTimeout)
+	}
+	timeout := indexwait.ParseTimeout(data.Timeout, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return 0, false
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	_, err := indexes.WaitForIndexBuild(waitCtx, bi.Client, &indexes.WaitForIndexBuildRequest{
 		OrganizationId: data.OrganizationId.ValueString(),
 		ProjectId:      data.ProjectId.ValueString(),
 		ClusterId:      data.ClusterId.ValueString(),
 		Bucket:         data.BucketName.ValueString(),
-		Collection:     collection,
+		IndexNames:     indexNames,
 		Scope:          scope,
-		IndexNames:     buildIndexes,
+		Collection:     collection,
+		OnStatusChange: func(indexName string, res *indexes.IndexBuildStatusResponse) {
+			status := res.Status
+			if res.Progress != nil {
+				status = fmt.Sprintf("%s, Progress: %d%%", status, *res.Progress)
+			}
+			resp.SendProgress(action.InvokeProgressEvent{
+				Message: fmt.Sprintf("Index: %s, Status: %s (elapsed %s)", indexName, status, time.Since(start).Round(time.Second)),
+			})
+		},
 	})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Build Deferred Indexes Failed",
-			fmt.Sprintf("Cannot build deferred indexes.  Error: %v\n", err.Error()),
-		)
-		return
+		indexwait.AddFailureDiagnostics(err, timeout, &resp.Diagnostics)
+		return 0, false
 	}
-
-	// Send a progress message back to Terraform
-	resp.SendProgress(action.InvokeProgressEvent{
-		Message: "finished action invocation, Indexes will be built in the background.",
-	})
-
+	return time.Since(start), true
 }
@@ -0,0 +1,146 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package datasources
+
+import (
+	"context"
+	"fmt"
+
+	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
+	"github.com/cdsre/terraform-provider-capellaextras/api/indexes"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/keyspace"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/providerdefaults"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &IndexBuildStatusDataSource{}
+var _ datasource.DataSourceWithConfigure = &IndexBuildStatusDataSource{}
+
+func NewIndexBuildStatusDataSource() datasource.DataSource {
+	return &IndexBuildStatusDataSource{}
+}
+
+// IndexBuildStatusDataSource reads the build status of a single index.
+type IndexBuildStatusDataSource struct {
+	client *apiclient.Client
+}
+
+// IndexBuildStatusModel describes the data source data model.
+type IndexBuildStatusModel struct {
+	OrganizationId types.String `tfsdk:"organization_id"`
+	ProjectId      types.String `tfsdk:"project_id"`
+	ClusterId      types.String `tfsdk:"cluster_id"`
+	BucketName     types.String `tfsdk:"bucket_name"`
+	ScopeName      types.String `tfsdk:"scope_name"`
+	CollectionName types.String `tfsdk:"collection_name"`
+	IndexName      types.String `tfsdk:"index_name"`
+	Status         types.String `tfsdk:"status"`
+}
+
+func (d *IndexBuildStatusDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_index_build_status"
+}
+
+func (d *IndexBuildStatusDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reads the current build status of a single Couchbase Capella query index. " +
+			"Useful in `precondition` and `check` blocks to gate on an index being `Ready`.",
+
+		Attributes: map[string]schema.Attribute{
+			"organization_id": schema.StringAttribute{
+				MarkdownDescription: "The organization ID where the index is located. Defaults to the provider `organization_id`.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "The project ID where the index is located. Defaults to the provider `project_id`.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"cluster_id": schema.StringAttribute{
+				MarkdownDescription: "The cluster ID where the index is located.",
+				Required:            true,
+			},
+			"bucket_name": schema.StringAttribute{
+				MarkdownDescription: "The bucket where the index is located.",
+				Required:            true,
+			},
+			"scope_name": schema.StringAttribute{
+				MarkdownDescription: "The scope where the index is located. Defaults to `_default`.",
+				Optional:            true,
+			},
+			"collection_name": schema.StringAttribute{
+				MarkdownDescription: "The collection where the index is located. Defaults to `_default`.",
+				Optional:            true,
+			},
+			"index_name": schema.StringAttribute{
+				MarkdownDescription: "The name of the index.",
+				Required:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "The current build status of the index, e.g. `Created`, `Building` or `Ready`.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *IndexBuildStatusDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiclient.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *IndexBuildStatusDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data IndexBuildStatusModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.OrganizationId, data.ProjectId = providerdefaults.ResolveOrgProject(d.client, "data source", data.OrganizationId, data.ProjectId, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	res, err := indexes.GetIndexBuildStatus(ctx, d.client, &indexes.IndexBuildStatusRequest{
+		OrganizationId: data.OrganizationId.ValueString(),
+		ProjectId:      data.ProjectId.ValueString(),
+		ClusterId:      data.ClusterId.ValueString(),
+		Bucket:         data.BucketName.ValueString(),
+		IndexName:      data.IndexName.ValueString(),
+		Scope:          keyspace.NameOrDefault(data.ScopeName),
+		Collection:     keyspace.NameOrDefault(data.CollectionName),
+	})
+	if err != nil {
+		if apiclient.IsNotFound(err) {
+			resp.Diagnostics.AddError(
+				"Index Not Found",
+				fmt.Sprintf("Index %q does not exist in the given keyspace: %v", data.IndexName.ValueString(), err),
+			)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Get Index Build Status Failed",
+			fmt.Sprintf("Cannot get build status for index %q: %v", data.IndexName.ValueString(), err),
+		)
+		return
+	}
+	data.Status = types.StringValue(res.Status)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,323 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package datasources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cdsre/terraform-provider-capellaextras/api/buckets"
+	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
+	"github.com/cdsre/terraform-provider-capellaextras/api/indexes"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/keyspace"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/providerdefaults"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &IndexHealthDataSource{}
+var _ datasource.DataSourceWithConfigure = &IndexHealthDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &IndexHealthDataSource{}
+
+func NewIndexHealthDataSource() datasource.DataSource {
+	return &IndexHealthDataSource{}
+}
+
+// IndexHealthDataSource summarises the build statuses of the indexes in a keyspace or cluster.
+type IndexHealthDataSource struct {
+	client *apiclient.Client
+}
+
+// IndexHealthModel describes the data source data model.
+type IndexHealthModel struct {
+	OrganizationId  types.String            `tfsdk:"organization_id"`
+	ProjectId       types.String            `tfsdk:"project_id"`
+	ClusterId       types.String            `tfsdk:"cluster_id"`
+	BucketName      types.String            `tfsdk:"bucket_name"`
+	ScopeName       types.String            `tfsdk:"scope_name"`
+	CollectionName  types.String            `tfsdk:"collection_name"`
+	Total           types.Int64             `tfsdk:"total"`
+	Ready           types.Int64             `tfsdk:"ready"`
+	Building        types.Int64             `tfsdk:"building"`
+	Deferred        types.Int64             `tfsdk:"deferred"`
+	Error           types.Int64             `tfsdk:"error"`
+	Offline         types.Int64             `tfsdk:"offline"`
+	Other           types.Int64             `tfsdk:"other"`
+	AllReady        types.Bool              `tfsdk:"all_ready"`
+	NotReadyIndexes map[string]types.String `tfsdk:"not_ready_indexes"`
+}
+
+// indexKeyspace is a bucket, scope and collection whose indexes are counted.
+type indexKeyspace struct {
+	bucket, scope, collection string
+}
+
+func (k indexKeyspace) String() string {
+	return k.bucket + "/" + k.scope + "/" + k.collection
+}
+
+func (d *IndexHealthDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_index_health"
+}
+
+func (d *IndexHealthDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Counts the query indexes of a Couchbase Capella keyspace, or of a whole cluster, by build status. " +
+			"Intended for `check` blocks that alert on indexes which are not `Ready`.",
+
+		Attributes: map[string]schema.Attribute{
+			"organization_id": schema.StringAttribute{
+				MarkdownDescription: "The organization ID where the cluster is located. Defaults to the provider `organization_id`.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "The project ID where the cluster is located. Defaults to the provider `project_id`.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"cluster_id": schema.StringAttribute{
+				MarkdownDescription: "The cluster ID whose indexes are counted.",
+				Required:            true,
+			},
+			"bucket_name": schema.StringAttribute{
+				MarkdownDescription: "The bucket of the keyspace to count indexes in. When unset, every collection of every bucket in the cluster is counted.",
+				Optional:            true,
+			},
+			"scope_name": schema.StringAttribute{
+				MarkdownDescription: "The scope of the keyspace to count indexes in. Defaults to `_default`. Requires `bucket_name`.",
+				Optional:            true,
+			},
+			"collection_name": schema.StringAttribute{
+				MarkdownDescription: "The collection of the keyspace to count indexes in. Defaults to `_default`. Requires `bucket_name`.",
+				Optional:            true,
+			},
+			"total": schema.Int64Attribute{
+				MarkdownDescription: "The number of indexes counted.",
+				Computed:            true,
+			},
+			"ready": schema.Int64Attribute{
+				MarkdownDescription: "The number of indexes whose status is `Ready`.",
+				Computed:            true,
+			},
+			"building": schema.Int64Attribute{
+				MarkdownDescription: "The number of indexes whose status is `Building`.",
+				Computed:            true,
+			},
+			"deferred": schema.Int64Attribute{
+				MarkdownDescription: "The number of deferred indexes that have not been built, whose status is `Created`.",
+				Computed:            true,
+			},
+			"error": schema.Int64Attribute{
+				MarkdownDescription: "The number of indexes whose status is `Error`.",
+				Computed:            true,
+			},
+			"offline": schema.Int64Attribute{
+				MarkdownDescription: "The number of indexes whose status is `Offline`.",
+				Computed:            true,
+			},
+			"other": schema.Int64Attribute{
+				MarkdownDescription: "The number of indexes with any other status.",
+				Computed:            true,
+			},
+			"all_ready": schema.BoolAttribute{
+				MarkdownDescription: "Whether every index counted is `Ready`. True when there are no indexes.",
+				Computed:            true,
+			},
+			"not_ready_indexes": schema.MapAttribute{
+				MarkdownDescription: "The status of each index that is not `Ready`, keyed by `{bucket}/{scope}/{collection}/{index}`.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *IndexHealthDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiclient.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *IndexHealthDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data IndexHealthModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() || !data.BucketName.IsNull() {
+		return
+	}
+
+	for _, attr := range []struct {
+		name  string
+		value types.String
+	}{
+		{"scope_name", data.ScopeName},
+		{"collection_name", data.CollectionName},
+	} {
+		if !attr.value.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attr.name),
+				"Missing Bucket Name",
+				fmt.Sprintf("%s can only be set together with bucket_name. Unset it to count the indexes of the whole cluster.", attr.name),
+			)
+		}
+	}
+}
+
+func (d *IndexHealthDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data IndexHealthModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.OrganizationId, data.ProjectId = providerdefaults.ResolveOrgProject(d.client, "data source", data.OrganizationId, data.ProjectId, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	keyspaces := []indexKeyspace{{
+		bucket:     data.BucketName.ValueString(),
+		scope:      keyspace.NameOrDefault(data.ScopeName),
+		collection: keyspace.NameOrDefault(data.CollectionName),
+	}}
+	if data.BucketName.IsNull() {
+		keyspaces = d.clusterKeyspaces(ctx, &data, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var counts struct{ total, ready, building, deferred, errored, offline, other int64 }
+	data.NotReadyIndexes = make(map[string]types.String)
+	for _, ks := range keyspaces {
+		list, err := indexes.ListIndexes(ctx, d.client, &indexes.ListIndexesRequest{
+			OrganizationId: data.OrganizationId.ValueString(),
+			ProjectId:      data.ProjectId.ValueString(),
+			ClusterId:      data.ClusterId.ValueString(),
+			Bucket:         ks.bucket,
+			Scope:          ks.scope,
+			Collection:     ks.collection,
+		})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"List Indexes Failed",
+				fmt.Sprintf("Cannot list indexes in keyspace %s: %v", ks, err),
+			)
+			return
+		}
+		if len(list.Definitions) == 0 {
+			continue
+		}
+
+		names := make([]string, 0, len(list.Definitions))
+		for _, ddl := range list.Definitions {
+			names = append(names, ddl.IndexName)
+		}
+		// Indexes dropped since they were listed are left out of the counts.
+		statuses, err := indexes.GetIndexBuildStatuses(ctx, d.client, &indexes.IndexBuildStatusesRequest{
+			OrganizationId: data.OrganizationId.ValueString(),
+			ProjectId:      data.ProjectId.ValueString(),
+			ClusterId:      data.ClusterId.ValueString(),
+			Bucket:         ks.bucket,
+			IndexNames:     names,
+			Scope:          ks.scope,
+			Collection:     ks.collection,
+		})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Get Index Build Status Failed",
+				fmt.Sprintf("Cannot get index build statuses in keyspace %s: %v", ks, err),
+			)
+			return
+		}
+
+		for name, status := range statuses {
+			counts.total++
+			switch status {
+			case "Ready":
+				counts.ready++
+				continue
+			case "Building":
+				counts.building++
+			case "Created":
+				counts.deferred++
+			case "Error":
+				counts.errored++
+			case "Offline":
+				counts.offline++
+			default:
+				counts.other++
+			}
+			data.NotReadyIndexes[ks.String()+"/"+name] = types.StringValue(status)
+		}
+	}
+
+	data.Total = types.Int64Value(counts.total)
+	data.Ready = types.Int64Value(counts.ready)
+	data.Building = types.Int64Value(counts.building)
+	data.Deferred = types.Int64Value(counts.deferred)
+	data.Error = types.Int64Value(counts.errored)
+	data.Offline = types.Int64Value(counts.offline)
+	data.Other = types.Int64Value(counts.other)
+	data.AllReady = types.BoolValue(counts.ready == counts.total)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// clusterKeyspaces returns every collection of every bucket in the cluster.
+func (d *IndexHealthDataSource) clusterKeyspaces(ctx context.Context, data *IndexHealthModel, diags *diag.Diagnostics) []indexKeyspace {
+	list, err := buckets.ListBuckets(ctx, d.client, &buckets.ListBucketsRequest{
+		OrganizationId: data.OrganizationId.ValueString(),
+		ProjectId:      data.ProjectId.ValueString(),
+		ClusterId:      data.ClusterId.ValueString(),
+	})
+	if err != nil {
+		diags.AddError(
+			"List Buckets Failed",
+			fmt.Sprintf("Cannot list buckets in cluster %q: %v", data.ClusterId.ValueString(), err),
+		)
+		return nil
+	}
+
+	var keyspaces []indexKeyspace
+	for _, b := range list {
+		scopes, err := buckets.ListScopes(ctx, d.client, &buckets.ListScopesRequest{
+			OrganizationId: data.OrganizationId.ValueString(),
+			ProjectId:      data.ProjectId.ValueString(),
+			ClusterId:      data.ClusterId.ValueString(),
+			BucketId:       b.ID,
+		})
+		if err != nil {
+			// The bucket was deleted between listing and reading it.
+			if apiclient.IsNotFound(err) {
+				continue
+			}
+			diags.AddError(
+				"List Scopes Failed",
+				fmt.Sprintf("Cannot list scopes in bucket %q: %v", b.Name, err),
+			)
+			return nil
+		}
+		for _, s := range scopes.Scopes {
+			for _, c := range s.Collections {
+				keyspaces = append(keyspaces, indexKeyspace{bucket: b.Name, scope: s.Name, collection: c.Name})
+			}
+		}
+	}
+	return keyspaces
+}
@@ -0,0 +1,240 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package datasources
+
+import (
+	"context"
+	"fmt"
+
+	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
+	"github.com/cdsre/terraform-provider-capellaextras/api/indexes"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/keyspace"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/providerdefaults"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &IndexesDataSource{}
+var _ datasource.DataSourceWithConfigure = &IndexesDataSource{}
+
+func NewIndexesDataSource() datasource.DataSource {
+	return &IndexesDataSource{}
+}
+
+// IndexesDataSource lists every index in a keyspace together with its definition.
+type IndexesDataSource struct {
+	client *apiclient.Client
+}
+
+// IndexesModel describes the data source data model.
+type IndexesModel struct {
+	OrganizationId types.String `tfsdk:"organization_id"`
+	ProjectId      types.String `tfsdk:"project_id"`
+	ClusterId      types.String `tfsdk:"cluster_id"`
+	BucketName     types.String `tfsdk:"bucket_name"`
+	ScopeName      types.String `tfsdk:"scope_name"`
+	CollectionName types.String `tfsdk:"collection_name"`
+	Indexes        []IndexModel `tfsdk:"indexes"`
+}
+
+// IndexModel describes a single index in the list.
+type IndexModel struct {
+	Name         types.String   `tfsdk:"name"`
+	Definition   types.String   `tfsdk:"definition"`
+	IsPrimary    types.Bool     `tfsdk:"is_primary"`
+	Keys         []types.String `tfsdk:"keys"`
+	Condition    types.String   `tfsdk:"condition"`
+	PartitionBy  types.String   `tfsdk:"partition_by"`
+	NumPartition types.Int64    `tfsdk:"num_partition"`
+	NumReplica   types.Int64    `tfsdk:"num_replica"`
+	Deferred     types.Bool     `tfsdk:"deferred"`
+	State        types.String   `tfsdk:"state"`
+}
+
+func (d *IndexesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_indexes"
+}
+
+func (d *IndexesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists every query index in a Couchbase Capella keyspace together with its definition and state.",
+
+		Attributes: map[string]schema.Attribute{
+			"organization_id": schema.StringAttribute{
+				MarkdownDescription: "The organization ID where the keyspace is located. Defaults to the provider `organization_id`.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "The project ID where the keyspace is located. Defaults to the provider `project_id`.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"cluster_id": schema.StringAttribute{
+				MarkdownDescription: "The cluster ID where the keyspace is located.",
+				Required:            true,
+			},
+			"bucket_name": schema.StringAttribute{
+				MarkdownDescription: "The bucket to list indexes for.",
+				Required:            true,
+			},
+			"scope_name": schema.StringAttribute{
+				MarkdownDescription: "The scope to list indexes for. Defaults to `_default`.",
+				Optional:            true,
+			},
+			"collection_name": schema.StringAttribute{
+				MarkdownDescription: "The collection to list indexes for. Defaults to `_default`.",
+				Optional:            true,
+			},
+			"indexes": schema.ListNestedAttribute{
+				MarkdownDescription: "The indexes in the keyspace.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the index.",
+							Computed:            true,
+						},
+						"definition": schema.StringAttribute{
+							MarkdownDescription: "The `CREATE INDEX` statement that recreates the index.",
+							Computed:            true,
+						},
+						"is_primary": schema.BoolAttribute{
+							MarkdownDescription: "Whether the index is a primary index.",
+							Computed:            true,
+						},
+						"keys": schema.ListAttribute{
+							MarkdownDescription: "The index key expressions. Empty for a primary index.",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+						"condition": schema.StringAttribute{
+							MarkdownDescription: "The `WHERE` clause of a partial index, or null.",
+							Computed:            true,
+						},
+						"partition_by": schema.StringAttribute{
+							MarkdownDescription: "The `PARTITION BY` expression of a partitioned index, or null.",
+							Computed:            true,
+						},
+						"num_partition": schema.Int64Attribute{
+							MarkdownDescription: "The number of partitions of a partitioned index.",
+							Computed:            true,
+						},
+						"num_replica": schema.Int64Attribute{
+							MarkdownDescription: "The number of index replicas.",
+							Computed:            true,
+						},
+						"deferred": schema.BoolAttribute{
+							MarkdownDescription: "Whether the index was created with `defer_build`.",
+							Computed:            true,
+						},
+						"state": schema.StringAttribute{
+							MarkdownDescription: "The current state of the index, e.g. `Created`, `Building` or `Ready`.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *IndexesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiclient.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *IndexesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data IndexesModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.OrganizationId, data.ProjectId = providerdefaults.ResolveOrgProject(d.client, "data source", data.OrganizationId, data.ProjectId, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	list, err := indexes.ListIndexes(ctx, d.client, &indexes.ListIndexesRequest{
+		OrganizationId: data.OrganizationId.ValueString(),
+		ProjectId:      data.ProjectId.ValueString(),
+		ClusterId:      data.ClusterId.ValueString(),
+		Bucket:         data.BucketName.ValueString(),
+		Scope:          keyspace.NameOrDefault(data.ScopeName),
+		Collection:     keyspace.NameOrDefault(data.CollectionName),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"List Indexes Failed",
+			fmt.Sprintf("Cannot list indexes in bucket %q: %v", data.BucketName.ValueString(), err),
+		)
+		return
+	}
+
+	names := make([]string, 0, len(list.Definitions))
+	for _, ddl := range list.Definitions {
+		names = append(names, ddl.IndexName)
+	}
+	details, err := indexes.GetIndexes(ctx, d.client, &indexes.GetIndexesRequest{
+		OrganizationId: data.OrganizationId.ValueString(),
+		ProjectId:      data.ProjectId.ValueString(),
+		ClusterId:      data.ClusterId.ValueString(),
+		Bucket:         data.BucketName.ValueString(),
+		Scope:          keyspace.NameOrDefault(data.ScopeName),
+		Collection:     keyspace.NameOrDefault(data.CollectionName),
+		IndexNames:     names,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Get Index Failed",
+			fmt.Sprintf("Cannot read indexes in bucket %q: %v", data.BucketName.ValueString(), err),
+		)
+		return
+	}
+
+	data.Indexes = make([]IndexModel, 0, len(list.Definitions))
+	for _, ddl := range list.Definitions {
+		idx, ok := details[ddl.IndexName]
+		if !ok {
+			// The index was dropped between listing and reading it.
+			continue
+		}
+		data.Indexes = append(data.Indexes, newIndexModel(ddl, idx))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func newIndexModel(ddl indexes.IndexDDL, idx *indexes.Index) IndexModel {
+	keys := make([]types.String, 0, len(idx.SecondaryExprs))
+	for _, k := range idx.SecondaryExprs {
+		keys = append(keys, types.StringValue(k))
+	}
+	return IndexModel{
+		Name:         types.StringValue(ddl.IndexName),
+		Definition:   types.StringValue(ddl.Definition),
+		IsPrimary:    types.BoolValue(idx.IsPrimary),
+		Keys:         keys,
+		Condition:    stringOrNull(idx.Where),
+		PartitionBy:  stringOrNull(idx.PartitionBy),
+		NumPartition: types.Int64Value(idx.NumPartition),
+		NumReplica:   types.Int64Value(idx.NumReplica),
+		Deferred:     types.BoolValue(idx.DeferBuild),
+		State:        types.StringValue(idx.Status),
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package ephemeralresources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cdsre/terraform-provider-capellaextras/api/apikeys"
+	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/providerdefaults"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ ephemeral.EphemeralResource = &APIKeyEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigure = &APIKeyEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithValidateConfig = &APIKeyEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithClose = &APIKeyEphemeralResource{}
+
+const (
+	// apiKeyPrivateKey is the private data key holding the API key to revoke on Close.
+	apiKeyPrivateKey = "api_key"
+	// defaultAPIKeyExpiry is the lifetime of the API key in days. Close revokes it earlier; the
+	// expiry only bounds the lifetime of a key left behind by an interrupted run.
+	defaultAPIKeyExpiry = 1
+	// maxAPIKeyExpiry is the longest lifetime Capella accepts, in days.
+	maxAPIKeyExpiry = 180
+	// defaultAPIKeyNamePrefix is prepended to the random suffix of generated API key names.
+	defaultAPIKeyNamePrefix = "tf-ephemeral-key-"
+	// apiKeyOrganizationRole is the only organization role granted, so a key never has
+	// organization-wide access beyond its project.
+	apiKeyOrganizationRole    = "organizationMember"
+	apiKeyProjectResourceType = "project"
+)
+
+func NewAPIKeyEphemeralResource() ephemeral.EphemeralResource {
+	return &APIKeyEphemeralResource{}
+}
+
+// APIKeyEphemeralResource creates a project-scoped API key on Open and revokes it on Close.
+type APIKeyEphemeralResource struct {
+	client *apiclient.Client
+}
+
+// APIKeyModel describes the ephemeral resource data model.
+type APIKeyModel struct {
+	OrganizationId types.String  `tfsdk:"organization_id"`
+	ProjectId      types.String  `tfsdk:"project_id"`
+	NamePrefix     types.String  `tfsdk:"name_prefix"`
+	Description    types.String  `tfsdk:"description"`
+	ProjectRoles   types.List    `tfsdk:"project_roles"`
+	AllowedCIDRs   types.List    `tfsdk:"allowed_cidrs"`
+	Expiry         types.Float64 `tfsdk:"expiry"`
+	Id             types.String  `tfsdk:"id"`
+	Name           types.String  `tfsdk:"name"`
+	Token          types.String  `tfsdk:"token"`
+}
+
+// apiKeyPrivate is the private data Close needs to revoke the API key.
+type apiKeyPrivate struct {
+	OrganizationId string `json:"organization_id"`
+	Id             string `json:"id"`
+}
+
+func (e *APIKeyEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_api_key"
+}
+
+func (e *APIKeyEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Creates a short-lived Couchbase Capella API key scoped to a project for the duration of a Terraform run " +
+			"and revokes it when Terraform no longer needs it. The token is never stored in state or plan files.",
+
+		Attributes: map[string]schema.Attribute{
+			"organization_id": schema.StringAttribute{
+				MarkdownDescription: "The organization ID to create the API key in. Defaults to the provider `organization_id`.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "The project ID the API key is scoped to. Defaults to the provider `project_id`.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"name_prefix": schema.StringAttribute{
+				MarkdownDescription: "The prefix of the generated API key name, followed by a random suffix. Defaults to `" + defaultAPIKeyNamePrefix + "`.",
+				Optional:            true,
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "The description of the API key.",
+				Optional:            true,
+			},
+			"project_roles": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "The roles granted on the project, e.g. `projectViewer`, `projectDataReader` or `projectDataReaderWriter`.",
+				Required:            true,
+			},
+			"allowed_cidrs": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "The CIDR blocks the API key can be used from. Defaults to any address.",
+				Optional:            true,
+			},
+			"expiry": schema.Float64Attribute{
+				MarkdownDescription: fmt.Sprintf("The lifetime of the API key in days, at most %d. The key is revoked when Terraform closes the ephemeral resource; "+
+					"the expiry only limits how long a key left behind by an interrupted run stays valid. Defaults to `%d`.", maxAPIKeyExpiry, defaultAPIKeyExpiry),
+				Optional: true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the API key.",
+				Computed:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The generated API key name.",
+				Computed:            true,
+			},
+			"token": schema.StringAttribute{
+				MarkdownDescription: "The API key token, for use as the `authentication_token` of a provider.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+func (e *APIKeyEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *apiclient.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	e.client = client
+}
+
+func (e *APIKeyEphemeralResource) ValidateConfig(ctx context.Context, req ephemeral.ValidateConfigRequest, resp *ephemeral.ValidateConfigResponse) {
+	var data APIKeyModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.ProjectRoles.IsNull() && !data.ProjectRoles.IsUnknown() && len(data.ProjectRoles.Elements()) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("project_roles"),
+			"Missing Project Roles",
+			"project_roles must contain at least one role.",
+		)
+	}
+	if !data.Expiry.IsNull() && !data.Expiry.IsUnknown() {
+		if expiry := data.Expiry.ValueFloat64(); expiry <= 0 || expiry > maxAPIKeyExpiry {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("expiry"),
+				"Invalid Expiry",
+				fmt.Sprintf("expiry must be greater than 0 and at most %d days, got %v.", maxAPIKeyExpiry, expiry),
+			)
+		}
+	}
+}
+
+func (e *APIKeyEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data APIKeyModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.OrganizationId, data.ProjectId = providerdefaults.ResolveOrgProject(e.client, "ephemeral resource", data.OrganizationId, data.ProjectId, &resp.Diagnostics)
+	apiReq := &apikeys.CreateAPIKeyRequest{
+		OrganizationId:    data.OrganizationId.ValueString(),
+		Description:       data.Description.ValueString(),
+		OrganizationRoles: []string{apiKeyOrganizationRole},
+		Expiry:            defaultAPIKeyExpiry,
+		Resources:         []apikeys.APIKeyScope{{Id: data.ProjectId.ValueString(), Type: apiKeyProjectResourceType}},
+	}
+	resp.Diagnostics.Append(data.ProjectRoles.ElementsAs(ctx, &apiReq.Resources[0].Roles, false)...)
+	if !data.AllowedCIDRs.IsNull() {
+		resp.Diagnostics.Append(data.AllowedCIDRs.ElementsAs(ctx, &apiReq.AllowedCIDRs, false)...)
+	}
+	if !data.Expiry.IsNull() {
+		apiReq.Expiry = data.Expiry.ValueFloat64()
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	prefix := defaultAPIKeyNamePrefix
+	if !data.NamePrefix.IsNull() {
+		prefix = data.NamePrefix.ValueString()
+	}
+	suffix, err := randomSuffix()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Generate API Key Name Failed",
+			fmt.Sprintf("Cannot generate an API key name: %v", err),
+		)
+		return
+	}
+	apiReq.Name = prefix + suffix
+	data.Name = types.StringValue(apiReq.Name)
+
+	res, err := apikeys.CreateAPIKey(ctx, e.client, apiReq)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Create API Key Failed",
+			fmt.Sprintf("Cannot create API key %q: %v", apiReq.Name, err),
+		)
+		return
+	}
+	data.Id = types.StringValue(res.Id)
+	data.Token = types.StringValue(res.Token)
+
+	private := apiKeyPrivate{
+		OrganizationId: data.OrganizationId.ValueString(),
+		Id:             res.Id,
+	}
+	resp.Diagnostics.Append(setPrivateJSON(ctx, resp.Private, apiKeyPrivateKey, private)...)
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		// Close is not called when Open fails, so do not leave the API key behind.
+		e.deleteAPIKey(ctx, private, &resp.Diagnostics)
+	}
+}
+
+func (e *APIKeyEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	var private apiKeyPrivate
+	if !getPrivateJSON(ctx, req.Private, apiKeyPrivateKey, &private, &resp.Diagnostics) {
+		return
+	}
+	e.deleteAPIKey(ctx, private, &resp.Diagnostics)
+}
+
+// deleteAPIKey revokes the API key, treating one that is already gone as revoked.
+func (e *APIKeyEphemeralResource) deleteAPIKey(ctx context.Context, private apiKeyPrivate, diags *diag.Diagnostics) {
+	err := apikeys.DeleteAPIKey(ctx, e.client, &apikeys.DeleteAPIKeyRequest{
+		OrganizationId: private.OrganizationId,
+		Id:             private.Id,
+	})
+	if err != nil && !apiclient.IsNotFound(err) {
+		diags.AddError(
+			"Delete API Key Failed",
+			fmt.Sprintf("Cannot revoke API key %q; delete it in Capella: %v", private.Id, err),
+		)
+	}
+}
@@ -0,0 +1,369 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package ephemeralresources
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
+	"github.com/cdsre/terraform-provider-capellaextras/api/credentials"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/providerdefaults"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ ephemeral.EphemeralResource = &DatabaseCredentialEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigure = &DatabaseCredentialEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithValidateConfig = &DatabaseCredentialEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithClose = &DatabaseCredentialEphemeralResource{}
+
+const (
+	defaultCredentialNamePrefix = "tf-ephemeral-"
+	// databaseCredentialPrivateKey is the private data key holding the credential to delete on Close.
+	databaseCredentialPrivateKey = "database_credential"
+	generatedPasswordLength      = 32
+)
+
+func NewDatabaseCredentialEphemeralResource() ephemeral.EphemeralResource {
+	return &DatabaseCredentialEphemeralResource{}
+}
+
+// DatabaseCredentialEphemeralResource creates a temporary database user on Open and deletes it on Close.
+type DatabaseCredentialEphemeralResource struct {
+	client *apiclient.Client
+}
+
+// DatabaseCredentialModel describes the ephemeral resource data model.
+type DatabaseCredentialModel struct {
+	OrganizationId types.String `tfsdk:"organization_id"`
+	ProjectId      types.String `tfsdk:"project_id"`
+	ClusterId      types.String `tfsdk:"cluster_id"`
+	NamePrefix     types.String `tfsdk:"name_prefix"`
+	Access         types.List   `tfsdk:"access"`
+	Id             types.String `tfsdk:"id"`
+	Username       types.String `tfsdk:"username"`
+	Password       types.String `tfsdk:"password"`
+}
+
+// DatabaseCredentialAccessModel describes an entry of access.
+type DatabaseCredentialAccessModel struct {
+	Privileges      types.List   `tfsdk:"privileges"`
+	BucketName      types.String `tfsdk:"bucket_name"`
+	ScopeName       types.String `tfsdk:"scope_name"`
+	CollectionNames types.List   `tfsdk:"collection_names"`
+}
+
+// databaseCredentialPrivate is the private data Close needs to delete the credential.
+type databaseCredentialPrivate struct {
+	OrganizationId string `json:"organization_id"`
+	ProjectId      string `json:"project_id"`
+	ClusterId      string `json:"cluster_id"`
+	Id             string `json:"id"`
+}
+
+func (e *DatabaseCredentialEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_database_credential"
+}
+
+func (e *DatabaseCredentialEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Creates a temporary Couchbase Capella database credential for the duration of a Terraform run " +
+			"and deletes it when Terraform no longer needs it. The username and password are never stored in state or plan files.",
+
+		Attributes: map[string]schema.Attribute{
+			"organization_id": schema.StringAttribute{
+				MarkdownDescription: "The organization ID where the cluster is located. Defaults to the provider `organization_id`.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "The project ID where the cluster is located. Defaults to the provider `project_id`.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"cluster_id": schema.StringAttribute{
+				MarkdownDescription: "The cluster ID to create the credential on.",
+				Required:            true,
+			},
+			"name_prefix": schema.StringAttribute{
+				MarkdownDescription: "The prefix of the generated username, followed by a random suffix. Defaults to `" + defaultCredentialNamePrefix + "`.",
+				Optional:            true,
+			},
+			"access": schema.ListNestedAttribute{
+				MarkdownDescription: "The privileges granted to the credential.",
+				Required:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"privileges": schema.ListAttribute{
+							ElementType:         types.StringType,
+							MarkdownDescription: "The privileges to grant, e.g. `data_reader` or `data_writer`.",
+							Required:            true,
+						},
+						"bucket_name": schema.StringAttribute{
+							MarkdownDescription: "The bucket the privileges apply to. When unset, they apply to every bucket in the cluster.",
+							Optional:            true,
+						},
+						"scope_name": schema.StringAttribute{
+							MarkdownDescription: "The scope of `bucket_name` the privileges apply to. When unset, they apply to every scope.",
+							Optional:            true,
+						},
+						"collection_names": schema.ListAttribute{
+							ElementType:         types.StringType,
+							MarkdownDescription: "The collections of `scope_name` the privileges apply to. When unset, they apply to every collection.",
+							Optional:            true,
+						},
+					},
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the database credential.",
+				Computed:            true,
+			},
+			"username": schema.StringAttribute{
+				MarkdownDescription: "The generated username.",
+				Computed:            true,
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "The generated password.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+func (e *DatabaseCredentialEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *apiclient.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	e.client = client
+}
+
+func (e *DatabaseCredentialEphemeralResource) ValidateConfig(ctx context.Context, req ephemeral.ValidateConfigRequest, resp *ephemeral.ValidateConfigResponse) {
+	var accessList types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("access"), &accessList)...)
+	if resp.Diagnostics.HasError() || accessList.IsNull() || accessList.IsUnknown() {
+		return
+	}
+	var entries []DatabaseCredentialAccessModel
+	resp.Diagnostics.Append(accessList.ElementsAs(ctx, &entries, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if len(entries) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("access"),
+			"Missing Access",
+			"access must contain at least one entry.",
+		)
+	}
+	for i, access := range entries {
+		if !access.Privileges.IsUnknown() && len(access.Privileges.Elements()) == 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("access").AtListIndex(i).AtName("privileges"),
+				"Missing Privileges",
+				"privileges must contain at least one privilege.",
+			)
+		}
+		if !access.ScopeName.IsNull() && access.BucketName.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("access").AtListIndex(i).AtName("scope_name"),
+				"Missing Bucket Name",
+				"scope_name can only be set together with bucket_name.",
+			)
+		}
+		if !access.CollectionNames.IsNull() && access.ScopeName.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("access").AtListIndex(i).AtName("collection_names"),
+				"Missing Scope Name",
+				"collection_names can only be set together with scope_name.",
+			)
+		}
+	}
+}
+
+func (e *DatabaseCredentialEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data DatabaseCredentialModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.OrganizationId, data.ProjectId = providerdefaults.ResolveOrgProject(e.client, "ephemeral resource", data.OrganizationId, data.ProjectId, &resp.Diagnostics)
+	access := credentialAccess(ctx, data.Access, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	prefix := defaultCredentialNamePrefix
+	if !data.NamePrefix.IsNull() {
+		prefix = data.NamePrefix.ValueString()
+	}
+	suffix, err := randomSuffix()
+	if err == nil {
+		data.Password, err = generatePassword()
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Generate Database Credential Failed",
+			fmt.Sprintf("Cannot generate a username and password: %v", err),
+		)
+		return
+	}
+	data.Username = types.StringValue(prefix + suffix)
+
+	res, err := credentials.CreateDatabaseCredential(ctx, e.client, &credentials.CreateDatabaseCredentialRequest{
+		OrganizationId: data.OrganizationId.ValueString(),
+		ProjectId:      data.ProjectId.ValueString(),
+		ClusterId:      data.ClusterId.ValueString(),
+		Name:           data.Username.ValueString(),
+		Password:       data.Password.ValueString(),
+		Access:         access,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Create Database Credential Failed",
+			fmt.Sprintf("Cannot create database credential %q: %v", data.Username.ValueString(), err),
+		)
+		return
+	}
+	data.Id = types.StringValue(res.Id)
+
+	private := databaseCredentialPrivate{
+		OrganizationId: data.OrganizationId.ValueString(),
+		ProjectId:      data.ProjectId.ValueString(),
+		ClusterId:      data.ClusterId.ValueString(),
+		Id:             res.Id,
+	}
+	resp.Diagnostics.Append(setPrivateJSON(ctx, resp.Private, databaseCredentialPrivateKey, private)...)
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		// Close is not called when Open fails, so do not leave the credential behind.
+		e.deleteCredential(ctx, private, &resp.Diagnostics)
+	}
+}
+
+func (e *DatabaseCredentialEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	var private databaseCredentialPrivate
+	if !getPrivateJSON(ctx, req.Private, databaseCredentialPrivateKey, &private, &resp.Diagnostics) {
+		return
+	}
+	e.deleteCredential(ctx, private, &resp.Diagnostics)
+}
+
+// deleteCredential deletes the credential, treating one that is already gone as deleted.
+func (e *DatabaseCredentialEphemeralResource) deleteCredential(ctx context.Context, private databaseCredentialPrivate, diags *diag.Diagnostics) {
+	err := credentials.DeleteDatabaseCredential(ctx, e.client, &credentials.DeleteDatabaseCredentialRequest{
+		OrganizationId: private.OrganizationId,
+		ProjectId:      private.ProjectId,
+		ClusterId:      private.ClusterId,
+		Id:             private.Id,
+	})
+	if err != nil && !apiclient.IsNotFound(err) {
+		diags.AddError(
+			"Delete Database Credential Failed",
+			fmt.Sprintf("Cannot delete database credential %q; delete it in Capella: %v", private.Id, err),
+		)
+	}
+}
+
+// credentialAccess converts the access attribute to the API request form.
+func credentialAccess(ctx context.Context, accessList types.List, diags *diag.Diagnostics) []credentials.Access {
+	var entries []DatabaseCredentialAccessModel
+	diags.Append(accessList.ElementsAs(ctx, &entries, false)...)
+
+	access := make([]credentials.Access, 0, len(entries))
+	for _, entry := range entries {
+		var a credentials.Access
+		diags.Append(entry.Privileges.ElementsAs(ctx, &a.Privileges, false)...)
+		if !entry.BucketName.IsNull() {
+			bucket := credentials.BucketAccess{Name: entry.BucketName.ValueString()}
+			if !entry.ScopeName.IsNull() {
+				scope := credentials.ScopeAccess{Name: entry.ScopeName.ValueString()}
+				if !entry.CollectionNames.IsNull() {
+					diags.Append(entry.CollectionNames.ElementsAs(ctx, &scope.Collections, false)...)
+				}
+				bucket.Scopes = []credentials.ScopeAccess{scope}
+			}
+			a.Resources = &credentials.AccessResources{Buckets: []credentials.BucketAccess{bucket}}
+		}
+		access = append(access, a)
+	}
+	return access
+}
+
+// randomSuffix returns 8 random hex characters that make a generated name unique.
+func randomSuffix() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// passwordClasses are the character classes of a generated password. Capella requires at least
+// one character of each.
+var passwordClasses = []string{
+	"ABCDEFGHJKLMNPQRSTUVWXYZ",
+	"abcdefghijkmnopqrstuvwxyz",
+	"23456789",
+	"#%+-=_",
+}
+
+// generatePassword returns a random password with at least one character of each passwordClasses.
+func generatePassword() (types.String, error) {
+	var all string
+	for _, class := range passwordClasses {
+		all += class
+	}
+
+	password := make([]byte, generatedPasswordLength)
+	for i := range password {
+		class := all
+		if i < len(passwordClasses) {
+			class = passwordClasses[i]
+		}
+		c, err := randomChar(class)
+		if err != nil {
+			return types.StringNull(), err
+		}
+		password[i] = c
+	}
+
+	// Move the required characters away from the start.
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return types.StringNull(), err
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+	return types.StringValue(string(password)), nil
+}
+
+func randomChar(chars string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+	if err != nil {
+		return 0, err
+	}
+	return chars[n.Int64()], nil
+}
@@ -0,0 +1,43 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package ephemeralresources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// privateData is the private data of an ephemeral resource, shared by OpenResponse and CloseRequest.
+type privateData interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// setPrivateJSON stores v as JSON under key.
+func setPrivateJSON(ctx context.Context, private privateData, key string, v any) diag.Diagnostics {
+	b, err := json.Marshal(v)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Store Private Data Failed", fmt.Sprintf("Cannot encode %s: %v", key, err))
+		return diags
+	}
+	return private.SetKey(ctx, key, b)
+}
+
+// getPrivateJSON decodes the JSON stored under key into v, reporting whether it was present.
+func getPrivateJSON(ctx context.Context, private privateData, key string, v any, diags *diag.Diagnostics) bool {
+	b, d := private.GetKey(ctx, key)
+	diags.Append(d...)
+	if diags.HasError() || len(b) == 0 {
+		return false
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		diags.AddError("Read Private Data Failed", fmt.Sprintf("Cannot decode %s: %v", key, err))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,71 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package functions
+
+import (
+	"context"
+
+	"github.com/cdsre/terraform-provider-capellaextras/api/indexes"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/keyspace"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = &BuildIndexStatementFunction{}
+
+func NewBuildIndexStatementFunction() function.Function {
+	return &BuildIndexStatementFunction{}
+}
+
+// BuildIndexStatementFunction renders the BUILD INDEX statement the provider submits for a keyspace.
+type BuildIndexStatementFunction struct{}
+
+func (f *BuildIndexStatementFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "build_index_statement"
+}
+
+func (f *BuildIndexStatementFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Renders a BUILD INDEX statement for deferred indexes in a keyspace.",
+		MarkdownDescription: "Returns the `BUILD INDEX` statement that builds the deferred indexes `index_names` in a keyspace, " +
+			"quoted exactly as `capellaextras_deferred_index_build` submits it.",
+		Parameters: keyspaceParameters(
+			function.ListParameter{
+				Name:                "index_names",
+				ElementType:         types.StringType,
+				MarkdownDescription: "The names of the indexes to build.",
+			},
+		),
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *BuildIndexStatementFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var bucket string
+	var scope, collection types.String
+	var indexNames []types.String
+	resp.Error = req.Arguments.Get(ctx, &bucket, &scope, &collection, &indexNames)
+	if resp.Error != nil {
+		return
+	}
+
+	if resp.Error = validateKeyspace(bucket); resp.Error != nil {
+		return
+	}
+	if len(indexNames) == 0 {
+		resp.Error = function.NewArgumentFuncError(3, "index_names must contain at least one index name.")
+		return
+	}
+	names := make([]string, len(indexNames))
+	for i, name := range indexNames {
+		if name.IsNull() || name.ValueString() == "" {
+			resp.Error = function.NewArgumentFuncError(3, "index_names must not contain null or empty names.")
+			return
+		}
+		names[i] = name.ValueString()
+	}
+
+	stmt := indexes.BuildIndexStatement(bucket, keyspace.NameOrDefault(scope), keyspace.NameOrDefault(collection), names)
+	resp.Error = resp.Result.Set(ctx, stmt)
+}
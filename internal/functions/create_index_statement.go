@@ -0,0 +1,259 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package functions
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cdsre/terraform-provider-capellaextras/api/indexes"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/keyspace"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = &CreateIndexStatementFunction{}
+
+func NewCreateIndexStatementFunction() function.Function {
+	return &CreateIndexStatementFunction{}
+}
+
+// CreateIndexStatementFunction renders the CREATE INDEX statement the provider submits for an index.
+type CreateIndexStatementFunction struct{}
+
+// createIndexOptionsArgument is the position of the options argument.
+const createIndexOptionsArgument = 5
+
+// createIndexOptions lists the attributes accepted in the options argument and their types.
+var createIndexOptions = map[string]string{
+	"is_primary":    "bool",
+	"where":         "string",
+	"partition_by":  "string",
+	"defer_build":   "bool",
+	"num_replica":   "number",
+	"num_partition": "number",
+	"nodes":         "list of string",
+}
+
+func (f *CreateIndexStatementFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "create_index_statement"
+}
+
+func (f *CreateIndexStatementFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Renders a CREATE INDEX statement for an index in a keyspace.",
+		MarkdownDescription: "Returns the `CREATE INDEX` statement for an index in a keyspace, " +
+			"quoted exactly as `capellaextras_query_index` submits it.",
+		Parameters: keyspaceParameters(
+			function.StringParameter{
+				Name:                "index_name",
+				MarkdownDescription: "The name of the index.",
+			},
+			function.ListParameter{
+				Name:           "index_keys",
+				ElementType:    types.StringType,
+				AllowNullValue: true,
+				MarkdownDescription: "The index key expressions, used verbatim. " +
+					"Required unless `options.is_primary` is `true`, in which case it must be `null` or empty.",
+			},
+			function.DynamicParameter{
+				Name:           "options",
+				AllowNullValue: true,
+				MarkdownDescription: "An object of optional settings, or `null`: `is_primary` (bool), `where` (string), " +
+					"`partition_by` (string) and the `WITH` options `defer_build` (bool), `num_replica` (number), " +
+					"`num_partition` (number) and `nodes` (list of string).",
+			},
+		),
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *CreateIndexStatementFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var bucket, indexName string
+	var scope, collection types.String
+	var indexKeys []types.String
+	var options types.Dynamic
+	resp.Error = req.Arguments.Get(ctx, &bucket, &scope, &collection, &indexName, &indexKeys, &options)
+	if resp.Error != nil {
+		return
+	}
+
+	if resp.Error = validateKeyspace(bucket); resp.Error != nil {
+		return
+	}
+	if indexName == "" {
+		resp.Error = function.NewArgumentFuncError(3, "index_name must not be empty.")
+		return
+	}
+
+	createReq := &indexes.CreateIndexRequest{
+		Bucket:     bucket,
+		Scope:      keyspace.NameOrDefault(scope),
+		Collection: keyspace.NameOrDefault(collection),
+		IndexName:  indexName,
+	}
+	for _, key := range indexKeys {
+		if key.IsNull() || strings.TrimSpace(key.ValueString()) == "" {
+			resp.Error = function.NewArgumentFuncError(4, "index_keys must not contain null, empty or whitespace-only keys.")
+			return
+		}
+		createReq.Keys = append(createReq.Keys, key.ValueString())
+	}
+	if resp.Error = applyCreateIndexOptions(options, createReq); resp.Error != nil {
+		return
+	}
+
+	if createReq.IsPrimary && (len(createReq.Keys) > 0 || createReq.Where != "" || createReq.PartitionBy != "") {
+		resp.Error = function.NewFuncError("index_keys, where and partition_by cannot be set on a primary index.")
+		return
+	}
+	if !createReq.IsPrimary && len(createReq.Keys) == 0 {
+		resp.Error = function.NewArgumentFuncError(4, "index_keys must contain at least one key unless options.is_primary is true.")
+		return
+	}
+	stmt, err := indexes.CreateIndexStatement(createReq)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, stmt)
+}
+
+// applyCreateIndexOptions sets the fields of createReq given in the options argument. Null
+// attributes are treated as unset.
+func applyCreateIndexOptions(options types.Dynamic, createReq *indexes.CreateIndexRequest) *function.FuncError {
+	if options.IsNull() || options.IsUnderlyingValueNull() {
+		return nil
+	}
+
+	var attrs map[string]attr.Value
+	switch v := options.UnderlyingValue().(type) {
+	case types.Object:
+		attrs = v.Attributes()
+	case types.Map:
+		attrs = v.Elements()
+	default:
+		return optionsError("options must be an object.")
+	}
+
+	with := &indexes.IndexWith{}
+	for name, value := range attrs {
+		if _, ok := createIndexOptions[name]; !ok {
+			return optionsError(fmt.Sprintf("options has unsupported attribute %q; supported attributes are %s.",
+				name, strings.Join(sortedKeys(createIndexOptions), ", ")))
+		}
+		if value.IsNull() {
+			continue
+		}
+
+		var err *function.FuncError
+		switch name {
+		case "is_primary":
+			err = optionBool(name, value, &createReq.IsPrimary)
+		case "where":
+			err = optionExpression(name, value, &createReq.Where)
+		case "partition_by":
+			err = optionExpression(name, value, &createReq.PartitionBy)
+		case "defer_build":
+			with.DeferBuild = new(bool)
+			err = optionBool(name, value, with.DeferBuild)
+		case "num_replica":
+			with.NumReplica = new(int64)
+			err = optionInt64(name, value, with.NumReplica)
+		case "num_partition":
+			with.NumPartition = new(int64)
+			err = optionInt64(name, value, with.NumPartition)
+		case "nodes":
+			err = optionStrings(name, value, &with.Nodes)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	createReq.With = with
+	return nil
+}
+
+func optionsError(text string) *function.FuncError {
+	return function.NewArgumentFuncError(createIndexOptionsArgument, text)
+}
+
+func optionTypeError(name string) *function.FuncError {
+	return optionsError(fmt.Sprintf("options.%s must be a %s.", name, createIndexOptions[name]))
+}
+
+func optionBool(name string, value attr.Value, target *bool) *function.FuncError {
+	v, ok := value.(types.Bool)
+	if !ok {
+		return optionTypeError(name)
+	}
+	*target = v.ValueBool()
+	return nil
+}
+
+func optionString(name string, value attr.Value, target *string) *function.FuncError {
+	v, ok := value.(types.String)
+	if !ok {
+		return optionTypeError(name)
+	}
+	*target = v.ValueString()
+	return nil
+}
+
+// optionExpression reads a N1QL expression, which must not be empty or whitespace-only.
+func optionExpression(name string, value attr.Value, target *string) *function.FuncError {
+	if err := optionString(name, value, target); err != nil {
+		return err
+	}
+	if strings.TrimSpace(*target) == "" {
+		return optionsError(fmt.Sprintf("options.%s must not be empty.", name))
+	}
+	return nil
+}
+
+func optionInt64(name string, value attr.Value, target *int64) *function.FuncError {
+	v, ok := value.(types.Number)
+	if !ok {
+		return optionTypeError(name)
+	}
+	n, accuracy := v.ValueBigFloat().Int64()
+	if !v.ValueBigFloat().IsInt() || accuracy != 0 {
+		return optionsError(fmt.Sprintf("options.%s must be a whole number.", name))
+	}
+	*target = n
+	return nil
+}
+
+// optionStrings reads a list of strings, which a Terraform list literal passes as a tuple.
+func optionStrings(name string, value attr.Value, target *[]string) *function.FuncError {
+	var elems []attr.Value
+	switch v := value.(type) {
+	case types.Tuple:
+		elems = v.Elements()
+	case types.List:
+		elems = v.Elements()
+	default:
+		return optionTypeError(name)
+	}
+	for _, elem := range elems {
+		s, ok := elem.(types.String)
+		if !ok || s.IsNull() {
+			return optionTypeError(name)
+		}
+		*target = append(*target, s.ValueString())
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,37 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package functions
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// keyspaceParameters returns the bucket, scope and collection parameters that lead the statement
+// functions, followed by params. Scope and collection may be null, meaning `_default`.
+func keyspaceParameters(params ...function.Parameter) []function.Parameter {
+	return append([]function.Parameter{
+		function.StringParameter{
+			Name:                "bucket",
+			MarkdownDescription: "The bucket of the keyspace.",
+		},
+		function.StringParameter{
+			Name:                "scope",
+			AllowNullValue:      true,
+			MarkdownDescription: "The scope of the keyspace. `null` means `_default`.",
+		},
+		function.StringParameter{
+			Name:                "collection",
+			AllowNullValue:      true,
+			MarkdownDescription: "The collection of the keyspace. `null` means `_default`.",
+		},
+	}, params...)
+}
+
+// validateKeyspace reports an empty bucket name, which no statement can target.
+func validateKeyspace(bucket string) *function.FuncError {
+	if bucket == "" {
+		return function.NewArgumentFuncError(0, "bucket must not be empty.")
+	}
+	return nil
+}
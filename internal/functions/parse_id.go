@@ -0,0 +1,204 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package functions
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = &ParseIDFunction{}
+
+func NewParseIDFunction() function.Function {
+	return &ParseIDFunction{}
+}
+
+// ParseIDFunction splits the composite IDs of this provider and of the Capella provider into
+// their components.
+type ParseIDFunction struct{}
+
+// idComponents are the attributes of the object returned by parse_id, in slash ID order.
+var idComponents = []string{
+	"organization_id",
+	"project_id",
+	"cluster_id",
+	"bucket_name",
+	"scope_name",
+	"collection_name",
+	"index_name",
+}
+
+// slashIDLengths are the component counts of the slash-separated IDs of this provider.
+var slashIDLengths = map[int]bool{
+	4: true, // capellaextras_deferred_index_build before scope and collection were added
+	5: true, // capellaextras_deferred_index_builds
+	6: true, // capellaextras_deferred_index_build
+	7: true, // capellaextras_query_index
+}
+
+// keyspacesIDMarker is the fourth component of a capellaextras_deferred_index_builds ID, which is
+// followed by a digest of its keyspaces rather than by keyspace components.
+const keyspacesIDMarker = "keyspaces"
+
+func (f *ParseIDFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_id"
+}
+
+// idAttributeTypes returns the attribute types of the object returned by parse_id.
+func idAttributeTypes() map[string]attr.Type {
+	attrTypes := make(map[string]attr.Type, len(idComponents))
+	for _, name := range idComponents {
+		attrTypes[name] = types.StringType
+	}
+	return attrTypes
+}
+
+func (f *ParseIDFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Parses a Capella resource ID into its components.",
+		MarkdownDescription: "Parses a composite ID into an object of `organization_id`, `project_id`, `cluster_id`, " +
+			"`bucket_name`, `scope_name`, `collection_name` and `index_name`. Components the ID does not contain are `null`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name: "id",
+				MarkdownDescription: "A slash-separated ID of this provider, such as " +
+					"`{organization_id}/{project_id}/{cluster_id}/{bucket_name}/{scope_name}/{collection_name}`, " +
+					"or a composite ID of `key=value` pairs or a JSON object, as used by the Capella provider.",
+			},
+		},
+		Return: function.ObjectReturn{AttributeTypes: idAttributeTypes()},
+	}
+}
+
+func (f *ParseIDFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var id string
+	if resp.Error = req.Arguments.Get(ctx, &id); resp.Error != nil {
+		return
+	}
+
+	components, err := parseID(strings.TrimSpace(id))
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Cannot parse ID %q: %v.", id, err))
+		return
+	}
+
+	values := make(map[string]attr.Value, len(idComponents))
+	for _, name := range idComponents {
+		values[name] = types.StringNull()
+		if v, ok := components[name]; ok {
+			values[name] = types.StringValue(v)
+		}
+	}
+	resp.Error = resp.Result.Set(ctx, types.ObjectValueMust(idAttributeTypes(), values))
+}
+
+// parseID returns the components of id keyed by idComponents names.
+func parseID(id string) (map[string]string, error) {
+	if strings.Contains(id, "=") || strings.HasPrefix(id, "{") {
+		pairs, err := compositeIDPairs(id)
+		if err != nil {
+			return nil, err
+		}
+		return compositeIDComponents(pairs)
+	}
+
+	parts := strings.Split(id, "/")
+	if !slashIDLengths[len(parts)] {
+		return nil, fmt.Errorf("expected 4 to 7 slash-separated components, got %d", len(parts))
+	}
+	if len(parts) == 5 {
+		if parts[3] != keyspacesIDMarker || parts[4] == "" {
+			return nil, fmt.Errorf("expected a 5 component ID of the form {organization_id}/{project_id}/{cluster_id}/%s/{digest}", keyspacesIDMarker)
+		}
+		// The digest identifies the keyspaces but does not contain them.
+		parts = parts[:3]
+	}
+	components := make(map[string]string, len(parts))
+	for i, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("component %d (%s) is empty", i+1, idComponents[i])
+		}
+		components[idComponents[i]] = part
+	}
+	return components, nil
+}
+
+// compositeIDPairs splits a composite ID into its keys and values. The ID is either a JSON
+// object of strings or comma-separated `key=value` pairs, optionally enclosed in braces.
+func compositeIDPairs(id string) (map[string]string, error) {
+	pairs := make(map[string]string)
+	if strings.HasPrefix(id, "{\"") {
+		if err := json.Unmarshal([]byte(id), &pairs); err != nil {
+			return nil, fmt.Errorf("invalid JSON object: %v", err)
+		}
+		return pairs, nil
+	}
+
+	id = strings.TrimSuffix(strings.TrimPrefix(id, "{"), "}")
+	for _, pair := range strings.Split(id, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		key, value = strings.Trim(strings.TrimSpace(key), `"`), strings.Trim(strings.TrimSpace(value), `"`)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%q is not a key=value pair", strings.TrimSpace(pair))
+		}
+		if _, dup := pairs[key]; dup {
+			return nil, fmt.Errorf("key %q is repeated", key)
+		}
+		pairs[key] = value
+	}
+	return pairs, nil
+}
+
+// compositeIDComponents maps the keys of a composite ID to idComponents names. A bucket_id is
+// decoded into the bucket name it encodes. The key id is ignored, as what it identifies depends on
+// the resource the ID came from.
+func compositeIDComponents(pairs map[string]string) (map[string]string, error) {
+	known := make(map[string]bool, len(idComponents))
+	for _, name := range idComponents {
+		known[name] = true
+	}
+
+	components := make(map[string]string, len(pairs))
+	for key, value := range pairs {
+		switch {
+		case key == "id":
+			continue
+		case key == "bucket_id":
+			name, err := decodeBucketID(value)
+			if err != nil {
+				return nil, err
+			}
+			if existing, ok := pairs["bucket_name"]; ok && existing != name {
+				return nil, fmt.Errorf("bucket_id %q does not match bucket_name %q", value, existing)
+			}
+			components["bucket_name"] = name
+			continue
+		case !known[key]:
+			return nil, fmt.Errorf("unsupported key %q", key)
+		}
+		if value == "" {
+			return nil, fmt.Errorf("%s is empty", key)
+		}
+		components[key] = value
+	}
+	return components, nil
+}
+
+// decodeBucketID returns the bucket name encoded in a Capella bucket ID, which is the base64
+// encoding of the name.
+func decodeBucketID(id string) (string, error) {
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if name, err := enc.DecodeString(id); err == nil && len(name) > 0 {
+			return string(name), nil
+		}
+	}
+	return "", fmt.Errorf("bucket_id %q is not a base64 encoded bucket name", id)
+}
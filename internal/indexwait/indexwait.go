@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package indexwait holds the timeout handling and diagnostics shared by the resources and
+// actions that wait for deferred index builds.
+package indexwait
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cdsre/terraform-provider-capellaextras/api/indexes"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// DefaultTimeout bounds how long a wait for triggered builds lasts when no timeout is configured.
+const DefaultTimeout = "20m"
+
+// ParseTimeout parses the timeout attribute, which must be a positive duration. It reports an
+// attribute error and returns zero when the value is invalid.
+func ParseTimeout(timeout types.String, diagnostics *diag.Diagnostics) time.Duration {
+	d, err := time.ParseDuration(timeout.ValueString())
+	if err == nil && d <= 0 {
+		err = errors.New("timeout must be a positive duration")
+	}
+	if err != nil {
+		diagnostics.AddAttributeError(
+			path.Root("timeout"),
+			"Invalid Timeout",
+			fmt.Sprintf("Invalid timeout %q: %v.", timeout.ValueString(), err),
+		)
+		return 0
+	}
+	return d
+}
+
+// AddFailureDiagnostics reports why WaitForIndexBuild returned err after waiting up to timeout.
+func AddFailureDiagnostics(err error, timeout time.Duration, diagnostics *diag.Diagnostics) {
+	var failed *indexes.IndexBuildFailedError
+	switch {
+	case errors.As(err, &failed):
+		diagnostics.AddError(
+			"Index Build Failed",
+			fmt.Sprintf("Index %q entered status %q while waiting for it to become ready.", failed.IndexName, failed.Status),
+		)
+	case errors.Is(err, context.DeadlineExceeded):
+		diagnostics.AddError(
+			"Timed Out Waiting For Index Build",
+			fmt.Sprintf("Indexes were still building after %s: %v", timeout, err),
+		)
+	default:
+		diagnostics.AddError(
+			"Wait For Index Build Failed",
+			fmt.Sprintf("Cannot wait for deferred index build: %v", err),
+		)
+	}
+}
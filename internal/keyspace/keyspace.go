@@ -0,0 +1,22 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package keyspace resolves the scope and collection names of a keyspace.
+package keyspace
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// DefaultName is the name of the default scope of a bucket and the default collection of a scope.
+const DefaultName = "_default"
+
+// NameOrDefault returns the given scope or collection name, or DefaultName when it is null,
+// unknown or empty. Every resource, data source, action and function resolves an unset scope or
+// collection this way.
+func NameOrDefault(v types.String) string {
+	if v.IsNull() || v.IsUnknown() || v.ValueString() == "" {
+		return DefaultName
+	}
+	return v.ValueString()
+}
@@ -0,0 +1,169 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cdsre/terraform-provider-capellaextras/api/apikeys"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+// mockAPIKeyServer serves the Capella API key API. POST apikeys records the request and returns a
+// new ID and token; DELETE apikeys/{id} revokes the key, or fails with 404 when it does not exist.
+type mockAPIKeyServer struct {
+	mu       sync.Mutex
+	created  []apikeys.CreateAPIKeyRequest
+	existing map[string]bool
+}
+
+func newMockAPIKeyServer() (*httptest.Server, *mockAPIKeyServer) {
+	m := &mockAPIKeyServer{existing: make(map[string]bool)}
+	return httptest.NewServer(m), m
+}
+
+func (m *mockAPIKeyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/apikeys"):
+		var req apikeys.CreateAPIKeyRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		m.created = append(m.created, req)
+		id := fmt.Sprintf("key-%d", len(m.created))
+		m.existing[id] = true
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(apikeys.CreateAPIKeyResponse{Id: id, Token: "token-" + id})
+
+	case r.Method == http.MethodDelete && strings.Contains(r.URL.Path, "/apikeys/"):
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		if !m.existing[id] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(m.existing, id)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (m *mockAPIKeyServer) getCreated() []apikeys.CreateAPIKeyRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]apikeys.CreateAPIKeyRequest(nil), m.created...)
+}
+
+func (m *mockAPIKeyServer) getExisting() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.existing)
+}
+
+func testAPIKeyConfig(serverURL, extra string) string {
+	return testDeferredIndexBuildProviderBlock(serverURL) + fmt.Sprintf(`
+ephemeral "capellaextras_api_key" "test" {
+  organization_id = %[1]q
+  project_id      = %[2]q
+  name_prefix     = "ci-"
+  project_roles   = ["projectDataReader"]
+%[3]s
+}
+
+provider "echo" {
+  data = ephemeral.capellaextras_api_key.test
+}
+
+resource "echo" "test" {}
+`, testOrgID, testProjID, extra)
+}
+
+// TestAccAPIKeyEphemeralResource verifies that Open creates an API key scoped to the project and
+// returns its token, and that Close revokes it.
+func TestAccAPIKeyEphemeralResource(t *testing.T) {
+	mockSrv, mock := newMockAPIKeyServer()
+	defer mockSrv.Close()
+
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactoriesWithEcho,
+		Steps: []resource.TestStep{
+			{
+				Config: testAPIKeyConfig(mockSrv.URL, `  expiry = 0.5`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("echo.test", tfjsonpath.New("data").AtMapKey("name"),
+						knownvalue.StringRegexp(regexp.MustCompile(`^ci-[0-9a-f]{8}$`))),
+					statecheck.ExpectKnownValue("echo.test", tfjsonpath.New("data").AtMapKey("id"),
+						knownvalue.StringRegexp(regexp.MustCompile(`^key-\d+$`))),
+					statecheck.ExpectKnownValue("echo.test", tfjsonpath.New("data").AtMapKey("token"),
+						knownvalue.StringRegexp(regexp.MustCompile(`^token-key-\d+$`))),
+				},
+			},
+		},
+	})
+
+	created := mock.getCreated()
+	if len(created) == 0 {
+		t.Fatal("expected an API key to be created")
+	}
+	got, _ := json.Marshal(created[0])
+	want := fmt.Sprintf(`{"name":%q,"organizationRoles":["organizationMember"],"expiry":0.5,"resources":[{"id":%q,"type":"project","roles":["projectDataReader"]}]}`,
+		created[0].Name, testProjID)
+	if string(got) != want {
+		t.Errorf("request = %s, want %s", got, want)
+	}
+	if n := mock.getExisting(); n != 0 {
+		t.Errorf("expected every API key to be revoked on close, %d remain", n)
+	}
+}
+
+// TestAccAPIKeyEphemeralResource_invalidExpiry verifies that an expiry outside what Capella
+// accepts is rejected.
+func TestAccAPIKeyEphemeralResource_invalidExpiry(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactoriesWithEcho,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAPIKeyConfig("http://127.0.0.1:0", `  expiry = 365`),
+				ExpectError: regexp.MustCompile(`Invalid Expiry`),
+			},
+		},
+	})
+}
+
+// TestAccAPIKeyEphemeralResource_organizationRoles verifies that organization roles cannot be
+// configured, so an API key never has more than organizationMember access to the organization.
+func TestAccAPIKeyEphemeralResource_organizationRoles(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactoriesWithEcho,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAPIKeyConfig("http://127.0.0.1:0", `  organization_roles = ["organizationOwner"]`),
+				ExpectError: regexp.MustCompile(`Unsupported argument`),
+			},
+		},
+	})
+}
@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+// TestAccBuildIndexStatementFunction verifies that the function quotes the keyspace and index
+// names like the BUILD INDEX statements the provider submits.
+func TestAccBuildIndexStatementFunction(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testDeferredIndexBuildProviderBlock("http://127.0.0.1:0") + `
+output "default_keyspace" {
+  value = provider::capellaextras::build_index_statement("travel-sample", null, null, ["idx1", "idx2"])
+}
+
+output "named_keyspace" {
+  value = provider::capellaextras::build_index_statement("travel-sample", "inventory", "route", ["idx` + "`" + `odd"])
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("default_keyspace",
+						"BUILD INDEX ON `travel-sample`.`_default`.`_default`(`idx1`, `idx2`)"),
+					resource.TestCheckOutput("named_keyspace",
+						"BUILD INDEX ON `travel-sample`.`inventory`.`route`(`idx``odd`)"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccBuildIndexStatementFunction_noIndexes verifies that an empty index list is rejected.
+func TestAccBuildIndexStatementFunction_noIndexes(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testDeferredIndexBuildProviderBlock("http://127.0.0.1:0") + `
+output "test" {
+  value = provider::capellaextras::build_index_statement("travel-sample", null, null, [])
+}
+`,
+				ExpectError: regexp.MustCompile(`index_names must contain at least one index name`),
+			},
+		},
+	})
+}
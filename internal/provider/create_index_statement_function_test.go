@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+// TestAccCreateIndexStatementFunction verifies that the function renders secondary and primary
+// indexes with their options like the CREATE INDEX statements the provider submits.
+func TestAccCreateIndexStatementFunction(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testDeferredIndexBuildProviderBlock("http://127.0.0.1:0") + `
+output "secondary" {
+  value = provider::capellaextras::create_index_statement(
+    "travel-sample", "inventory", "route", "idx_by_airline",
+    ["airline", "sourceairport"],
+    {
+      where       = "type = \"route\""
+      defer_build = true
+      num_replica = 1
+      nodes       = ["node1:9101"]
+    },
+  )
+}
+
+output "plain" {
+  value = provider::capellaextras::create_index_statement("travel-sample", null, null, "idx_plain", ["name"], null)
+}
+
+output "primary" {
+  value = provider::capellaextras::create_index_statement("travel-sample", null, null, "#primary", null, { is_primary = true })
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("secondary",
+						"CREATE INDEX `idx_by_airline` ON `travel-sample`.`inventory`.`route`(airline, sourceairport) "+
+							`WHERE type = "route" WITH {"defer_build":true,"num_replica":1,"nodes":["node1:9101"]}`),
+					resource.TestCheckOutput("plain",
+						"CREATE INDEX `idx_plain` ON `travel-sample`.`_default`.`_default`(name)"),
+					resource.TestCheckOutput("primary",
+						"CREATE PRIMARY INDEX `#primary` ON `travel-sample`.`_default`.`_default`"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccCreateIndexStatementFunction_invalid verifies that inconsistent arguments are rejected.
+func TestAccCreateIndexStatementFunction_invalid(t *testing.T) {
+	testCases := map[string]struct {
+		call      string
+		wantError string
+	}{
+		"missing keys": {
+			call:      `("travel-sample", null, null, "idx", [], null)`,
+			wantError: `index_keys must contain at least one key`,
+		},
+		"empty key": {
+			call:      `("travel-sample", null, null, "idx", ["name", ""], null)`,
+			wantError: `index_keys must not contain null, empty or whitespace-only keys`,
+		},
+		"whitespace-only key": {
+			call:      `("travel-sample", null, null, "idx", ["  "], null)`,
+			wantError: `index_keys must not contain null, empty or whitespace-only keys`,
+		},
+		"empty where": {
+			call:      `("travel-sample", null, null, "idx", ["name"], { where = "" })`,
+			wantError: `options.where must not be empty`,
+		},
+		"whitespace-only partition_by": {
+			call:      `("travel-sample", null, null, "idx", ["name"], { partition_by = " " })`,
+			wantError: `options.partition_by must not be empty`,
+		},
+		"primary with keys": {
+			call:      `("travel-sample", null, null, "idx", ["name"], { is_primary = true })`,
+			wantError: `cannot be set on a primary index`,
+		},
+		"unsupported option": {
+			call:      `("travel-sample", null, null, "idx", ["name"], { defer = true })`,
+			wantError: `unsupported attribute "defer"`,
+		},
+		"mistyped option": {
+			call:      `("travel-sample", null, null, "idx", ["name"], { num_replica = "one" })`,
+			wantError: `options.num_replica must be a number`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			resource.UnitTest(t, resource.TestCase{
+				TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+					tfversion.SkipBelow(tfversion.Version1_8_0),
+				},
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config: testDeferredIndexBuildProviderBlock("http://127.0.0.1:0") + `
+output "test" {
+  value = provider::capellaextras::create_index_statement` + tc.call + `
+}
+`,
+						ExpectError: regexp.MustCompile(tc.wantError),
+					},
+				},
+			})
+		})
+	}
+}
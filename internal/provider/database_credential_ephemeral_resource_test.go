@@ -0,0 +1,156 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cdsre/terraform-provider-capellaextras/api/credentials"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+// mockCredentialServer serves the Capella database credential API. POST users records the
+// request and returns a new ID; DELETE users/{id} removes the credential, or fails with 404 when
+// it does not exist.
+type mockCredentialServer struct {
+	mu       sync.Mutex
+	created  []credentials.CreateDatabaseCredentialRequest
+	existing map[string]bool
+	deleted  []string
+}
+
+func newMockCredentialServer() (*httptest.Server, *mockCredentialServer) {
+	m := &mockCredentialServer{existing: make(map[string]bool)}
+	return httptest.NewServer(m), m
+}
+
+func (m *mockCredentialServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/users"):
+		var req credentials.CreateDatabaseCredentialRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		m.created = append(m.created, req)
+		id := fmt.Sprintf("user-%d", len(m.created))
+		m.existing[id] = true
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(credentials.CreateDatabaseCredentialResponse{Id: id})
+
+	case r.Method == http.MethodDelete && strings.Contains(r.URL.Path, "/users/"):
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		if !m.existing[id] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(m.existing, id)
+		m.deleted = append(m.deleted, id)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (m *mockCredentialServer) getCreated() []credentials.CreateDatabaseCredentialRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]credentials.CreateDatabaseCredentialRequest(nil), m.created...)
+}
+
+func (m *mockCredentialServer) getExisting() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.existing)
+}
+
+func testDatabaseCredentialConfig(serverURL, access string) string {
+	return testDeferredIndexBuildProviderBlock(serverURL) + fmt.Sprintf(`
+ephemeral "capellaextras_database_credential" "test" {
+  organization_id = %[1]q
+  project_id      = %[2]q
+  cluster_id      = %[3]q
+  name_prefix     = "ci-"
+  access          = %[4]s
+}
+
+provider "echo" {
+  data = ephemeral.capellaextras_database_credential.test
+}
+
+resource "echo" "test" {}
+`, testOrgID, testProjID, testClusterID, access)
+}
+
+// TestAccDatabaseCredentialEphemeralResource verifies that Open creates a credential with the
+// configured access and a generated username and password, and that Close deletes it.
+func TestAccDatabaseCredentialEphemeralResource(t *testing.T) {
+	mockSrv, mock := newMockCredentialServer()
+	defer mockSrv.Close()
+
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactoriesWithEcho,
+		Steps: []resource.TestStep{
+			{
+				Config: testDatabaseCredentialConfig(mockSrv.URL, fmt.Sprintf(`[
+    { privileges = ["data_reader", "data_writer"], bucket_name = %q, scope_name = "inventory" },
+  ]`, testBucket)),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("echo.test", tfjsonpath.New("data").AtMapKey("username"),
+						knownvalue.StringRegexp(regexp.MustCompile(`^ci-[0-9a-f]{8}$`))),
+					statecheck.ExpectKnownValue("echo.test", tfjsonpath.New("data").AtMapKey("password"),
+						knownvalue.StringRegexp(regexp.MustCompile(`^\S{32}$`))),
+					statecheck.ExpectKnownValue("echo.test", tfjsonpath.New("data").AtMapKey("id"),
+						knownvalue.StringRegexp(regexp.MustCompile(`^user-\d+$`))),
+				},
+			},
+		},
+	})
+
+	created := mock.getCreated()
+	if len(created) == 0 {
+		t.Fatal("expected a database credential to be created")
+	}
+	access, _ := json.Marshal(created[0].Access)
+	want := fmt.Sprintf(`[{"privileges":["data_reader","data_writer"],"resources":{"buckets":[{"name":%q,"scopes":[{"name":"inventory"}]}]}}]`, testBucket)
+	if string(access) != want {
+		t.Errorf("access = %s, want %s", access, want)
+	}
+	if n := mock.getExisting(); n != 0 {
+		t.Errorf("expected every credential to be deleted on close, %d remain", n)
+	}
+}
+
+// TestAccDatabaseCredentialEphemeralResource_scopeWithoutBucket verifies that a scope without a
+// bucket is rejected.
+func TestAccDatabaseCredentialEphemeralResource_scopeWithoutBucket(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactoriesWithEcho,
+		Steps: []resource.TestStep{
+			{
+				Config:      testDatabaseCredentialConfig("http://127.0.0.1:0", `[{ privileges = ["data_reader"], scope_name = "inventory" }]`),
+				ExpectError: regexp.MustCompile(`Missing Bucket Name`),
+			},
+		},
+	})
+}
@@ -9,11 +9,16 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"testing"
 
+	"github.com/cdsre/terraform-provider-capellaextras/api/buckets"
+	"github.com/cdsre/terraform-provider-capellaextras/api/indexes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
 
 // mockIndexServer serves a minimal Capella index API for testing.
@@ -27,8 +32,34 @@ import (
 // runs).  This lets a single resource.TestStep verify the full single-apply scenario.
 //
 // On POST queryService/indexes the server increments buildCallCount and transitions
-// every trigger-status index to "Building" so post-apply Reads return a non-trigger
-// status and the empty-plan idempotency check passes.
+// the trigger-status indexes named in the statement to "Building" so post-apply Reads
+// return a non-trigger status and the empty-plan idempotency check passes.
+//
+// Authentication: when requiredHeaders is set, any request missing one of the headers
+// (or carrying a different value) is rejected with 401, as the real API would.
+//
+// Definitions: GET queryService/indexes lists every index in indexStatuses (sorted by name) and
+// GET queryService/indexes/{name} returns its properties, taken from indexDefinitions when set
+// via setIndexDefinition and with the current status filled in.
+//
+// DDL: a POST whose definition is a CREATE INDEX statement adds the index — "Created" when
+// it is deferred, "Ready" otherwise — and records the statement in createStatements. DELETE
+// queryService/indexes/{name} drops the index and counts the call in dropCallCount.
+//
+// BUILD INDEX statements are recorded in buildStatements, and only the indexes they name move
+// to "Building". When rejectBuildOf is set, a statement naming that index fails with 400; when
+// partialBuildErrorOf is set, that index is left alone and reported in the response error field.
+//
+// Status details: build status responses include the progress, replicas, hosts and error set
+// via setStatusDetails for that index.
+//
+// Buckets: GET buckets lists the buckets set via setBuckets, with the bucket name as its ID, and
+// GET buckets/{id}/scopes returns the scopes of that bucket. Index lists ignore the keyspace, so
+// every collection reports the same indexes.
+//
+// Building indexes: when buildingResolvesTo is set, a GET that reports "Building" moves
+// the index to that status for subsequent GETs, simulating a build finishing (or failing)
+// while the provider waits on it.
 type mockIndexServer struct {
 	mu              sync.Mutex
 	indexStatuses   map[string]string
@@ -37,14 +68,37 @@ type mockIndexServer struct {
 	// pendingIndexes: absent on first GET, then promoted to the stored status.
 	pendingIndexes map[string]string
 	getCallCounts  map[string]int
+	// buildingResolvesTo: status a "Building" index moves to after it has been reported once.
+	buildingResolvesTo string
+	// requiredHeaders: headers every request must carry, e.g. the expected credentials.
+	requiredHeaders map[string]string
+	// indexDefinitions: properties returned by GET queryService/indexes/{name}.
+	indexDefinitions map[string]indexes.Index
+	// createStatements: the most recent CREATE INDEX statement per index.
+	createStatements map[string]string
+	dropCallCount    int
+	// buildStatements: every BUILD INDEX statement received, in order.
+	buildStatements []string
+	// rejectBuildOf: a BUILD INDEX statement naming this index fails with 400.
+	rejectBuildOf string
+	// partialBuildErrorOf: a BUILD INDEX statement naming this index succeeds for the other
+	// indexes and reports this one in the error field of the response.
+	partialBuildErrorOf string
+	// statusDetails: extra fields of the build status response per index; Status is ignored.
+	statusDetails map[string]indexes.IndexBuildStatusResponse
+	// bucketScopes: the scopes of each bucket in the cluster, keyed by bucket name.
+	bucketScopes map[string][]buckets.Scope
 }
 
 func newMockIndexServer(statuses map[string]string) (*httptest.Server, *mockIndexServer) {
 	m := &mockIndexServer{
-		indexStatuses:   statuses,
-		triggerStatuses: map[string]bool{"Created": true},
-		pendingIndexes:  make(map[string]string),
-		getCallCounts:   make(map[string]int),
+		indexStatuses:    statuses,
+		triggerStatuses:  map[string]bool{"Created": true},
+		pendingIndexes:   make(map[string]string),
+		getCallCounts:    make(map[string]int),
+		indexDefinitions: make(map[string]indexes.Index),
+		createStatements: make(map[string]string),
+		statusDetails:    make(map[string]indexes.IndexBuildStatusResponse),
 	}
 	return httptest.NewServer(m), m
 }
@@ -66,18 +120,185 @@ func (m *mockIndexServer) setPending(indexName, status string) {
 	m.getCallCounts[indexName] = 0
 }
 
+func (m *mockIndexServer) setBuildingResolvesTo(status string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.buildingResolvesTo = status
+}
+
+func (m *mockIndexServer) setRequiredHeaders(headers map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requiredHeaders = headers
+}
+
+func (m *mockIndexServer) setIndexDefinition(idx indexes.Index) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.indexDefinitions[idx.IndexName] = idx
+}
+
+func (m *mockIndexServer) setStatusDetails(indexName string, details indexes.IndexBuildStatusResponse) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.statusDetails[indexName] = details
+}
+
+func (m *mockIndexServer) setBuckets(bucketScopes map[string][]buckets.Scope) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bucketScopes = bucketScopes
+}
+
 func (m *mockIndexServer) getBuildCallCount() int {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	return m.buildCallCount
 }
 
+func (m *mockIndexServer) getCreateStatement(indexName string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.createStatements[indexName]
+}
+
+func (m *mockIndexServer) getDropCallCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.dropCallCount
+}
+
+func (m *mockIndexServer) setRejectBuildOf(indexName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rejectBuildOf = indexName
+}
+
+func (m *mockIndexServer) setPartialBuildErrorOf(indexName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.partialBuildErrorOf = indexName
+}
+
+func (m *mockIndexServer) getBuildStatements() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.buildStatements...)
+}
+
+// buildIndexNamePattern matches each quoted index name in the list of a BUILD INDEX statement.
+var buildIndexNamePattern = regexp.MustCompile("`((?:[^`]|``)+)`")
+
+// buildIndexNames returns the index names listed in a BUILD INDEX statement.
+func buildIndexNames(stmt string) []string {
+	open := strings.Index(stmt, "(")
+	if open < 0 {
+		return nil
+	}
+	var names []string
+	for _, m := range buildIndexNamePattern.FindAllStringSubmatch(stmt[open:], -1) {
+		names = append(names, strings.ReplaceAll(m[1], "``", "`"))
+	}
+	return names
+}
+
+// createIndexNamePattern extracts the index name from a CREATE [PRIMARY] INDEX statement.
+var createIndexNamePattern = regexp.MustCompile("^CREATE (?:PRIMARY )?INDEX `([^`]+)`")
+
+// simpleIdentifierPattern matches an unquoted identifier, which the query service reports quoted.
+var simpleIdentifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// indexFromCreateStatement returns the properties the query service reports for an index created
+// by stmt, a statement generated by indexes.CreateIndexStatement. Like the query service, it
+// quotes simple identifiers among the keys and parenthesises the condition.
+func indexFromCreateStatement(indexName, stmt string) indexes.Index {
+	idx := indexes.Index{IndexName: indexName, IsPrimary: strings.HasPrefix(stmt, "CREATE PRIMARY ")}
+	if i := strings.LastIndex(stmt, " WITH {"); i >= 0 {
+		var with indexes.IndexWith
+		_ = json.Unmarshal([]byte(stmt[i+len(" WITH "):]), &with)
+		stmt = stmt[:i]
+		idx.DeferBuild = with.DeferBuild != nil && *with.DeferBuild
+		if with.NumReplica != nil {
+			idx.NumReplica = *with.NumReplica
+		}
+		if with.NumPartition != nil {
+			idx.NumPartition = *with.NumPartition
+		}
+	}
+	if idx.IsPrimary {
+		return idx
+	}
+
+	// The keys are the parenthesised list following the keyspace.
+	start := strings.Index(stmt, "`(")
+	depth, end := 0, len(stmt)
+	for i := start + 1; i < len(stmt); i++ {
+		switch stmt[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		if depth == 0 {
+			end = i
+			break
+		}
+	}
+	keyStart := start + 2
+	var keys []string
+	for i := keyStart; i < end; i++ {
+		switch {
+		case stmt[i] == '(':
+			depth++
+		case stmt[i] == ')':
+			depth--
+		case stmt[i] == ',' && depth == 0:
+			keys = append(keys, stmt[keyStart:i])
+			keyStart = i + 1
+		}
+	}
+	keys = append(keys, stmt[keyStart:end])
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		if simpleIdentifierPattern.MatchString(key) {
+			key = "`" + key + "`"
+		}
+		idx.SecondaryExprs = append(idx.SecondaryExprs, key)
+	}
+
+	rest := stmt[end+1:]
+	if i := strings.Index(rest, " WHERE "); i >= 0 {
+		idx.Where = "(" + rest[i+len(" WHERE "):] + ")"
+		rest = rest[:i]
+	}
+	idx.PartitionBy = strings.TrimPrefix(rest, " PARTITION BY ")
+	return idx
+}
+
 func (m *mockIndexServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	w.Header().Set("Content-Type", "application/json")
 
+	for name, want := range m.requiredHeaders {
+		if r.Header.Get(name) != want {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"code":    "unauthorized",
+				"message": fmt.Sprintf("missing or invalid %s header", name),
+			})
+			return
+		}
+	}
+
+	var stmt string
+	if r.Method == http.MethodPost {
+		var def indexes.IndexDefinition
+		_ = json.NewDecoder(r.Body).Decode(&def)
+		stmt = def.Definition
+	}
+
 	switch {
 	case r.Method == http.MethodGet && strings.Contains(r.URL.Path, "indexBuildStatus"):
 		parts := strings.Split(r.URL.Path, "/")
@@ -108,18 +329,134 @@ func (m *mockIndexServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			})
 			return
 		}
-		_ = json.NewEncoder(w).Encode(map[string]string{"status": status})
+		res := m.statusDetails[indexName]
+		res.Status = status
+		_ = json.NewEncoder(w).Encode(res)
+		if status == "Building" && m.buildingResolvesTo != "" {
+			m.indexStatuses[indexName] = m.buildingResolvesTo
+		}
+
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/queryService/indexes"):
+		names := make([]string, 0, len(m.indexStatuses))
+		for name := range m.indexStatuses {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		res := indexes.ListIndexesResponse{Definitions: []indexes.IndexDDL{}}
+		for _, name := range names {
+			res.Definitions = append(res.Definitions, indexes.IndexDDL{
+				IndexName:  name,
+				Definition: fmt.Sprintf("CREATE INDEX `%s` ON `%s`", name, r.URL.Query().Get("bucket")),
+			})
+		}
+		_ = json.NewEncoder(w).Encode(res)
+
+	case r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/queryService/indexes/"):
+		parts := strings.Split(r.URL.Path, "/")
+		indexName, _ := url.PathUnescape(parts[len(parts)-1])
+		status, ok := m.indexStatuses[indexName]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"code":    "not_found",
+				"message": fmt.Sprintf("index %q not found", indexName),
+			})
+			return
+		}
+		idx, ok := m.indexDefinitions[indexName]
+		if !ok {
+			idx = indexes.Index{IndexName: indexName}
+		}
+		idx.Status = status
+		_ = json.NewEncoder(w).Encode(idx)
+
+	case r.Method == http.MethodDelete && strings.Contains(r.URL.Path, "/queryService/indexes/"):
+		parts := strings.Split(r.URL.Path, "/")
+		indexName, _ := url.PathUnescape(parts[len(parts)-1])
+		if _, ok := m.indexStatuses[indexName]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"code":    "not_found",
+				"message": fmt.Sprintf("index %q not found", indexName),
+			})
+			return
+		}
+		m.dropCallCount++
+		delete(m.indexStatuses, indexName)
+		delete(m.indexDefinitions, indexName)
+
+	case r.Method == http.MethodPost && strings.HasPrefix(stmt, "CREATE "):
+		indexName := createIndexNamePattern.FindStringSubmatch(stmt)[1]
+		if _, exists := m.indexStatuses[indexName]; exists {
+			w.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"code":    "conflict",
+				"message": fmt.Sprintf("index %q already exists", indexName),
+			})
+			return
+		}
+		m.createStatements[indexName] = stmt
+		m.indexDefinitions[indexName] = indexFromCreateStatement(indexName, stmt)
+		if strings.Contains(stmt, `"defer_build":true`) {
+			m.indexStatuses[indexName] = "Created"
+		} else {
+			m.indexStatuses[indexName] = "Ready"
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{})
 
 	case r.Method == http.MethodPost && strings.Contains(r.URL.Path, "/queryService/indexes"):
 		m.buildCallCount++
-		// Transition trigger-status indexes to "Building" so the post-apply Read
-		// returns a non-trigger status and the empty-plan check passes.
-		for idx, status := range m.indexStatuses {
-			if m.triggerStatuses[status] {
+		m.buildStatements = append(m.buildStatements, stmt)
+		names := buildIndexNames(stmt)
+		for _, idx := range names {
+			if idx == m.rejectBuildOf {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(map[string]string{
+					"code":    "bad_request",
+					"message": fmt.Sprintf("cannot build index %q", idx),
+				})
+				return
+			}
+		}
+		// Transition the named trigger-status indexes to "Building" so the post-apply
+		// Read returns a non-trigger status and the empty-plan check passes.
+		res := map[string]interface{}{}
+		for _, idx := range names {
+			if idx == m.partialBuildErrorOf {
+				res["error"] = fmt.Sprintf("Build index fails. Index %s is already being built.", idx)
+				continue
+			}
+			if m.triggerStatuses[m.indexStatuses[idx]] {
 				m.indexStatuses[idx] = "Building"
 			}
 		}
-		_ = json.NewEncoder(w).Encode(map[string]interface{}{})
+		_ = json.NewEncoder(w).Encode(res)
+
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/buckets"):
+		names := make([]string, 0, len(m.bucketScopes))
+		for name := range m.bucketScopes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		res := map[string][]buckets.Bucket{"data": {}}
+		for _, name := range names {
+			res["data"] = append(res["data"], buckets.Bucket{ID: name, Name: name})
+		}
+		_ = json.NewEncoder(w).Encode(res)
+
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/scopes"):
+		parts := strings.Split(r.URL.Path, "/")
+		bucketID, _ := url.PathUnescape(parts[len(parts)-2])
+		scopes, ok := m.bucketScopes[bucketID]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"code":    "not_found",
+				"message": fmt.Sprintf("bucket %q not found", bucketID),
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(buckets.ListScopesResponse{Scopes: scopes})
 
 	default:
 		w.WriteHeader(http.StatusNotFound)
@@ -174,6 +511,41 @@ resource "capellaextras_deferred_index_build" "test" {
 `, orgID, projID, clusterID, bucket, strings.Join(quotedIdx, ", "), strings.Join(quotedTrig, ", "))
 }
 
+func testDeferredIndexBuildConfigWithWait(serverURL, orgID, projID, clusterID, bucket string, indexNames []string, timeout string) string {
+	quoted := make([]string, len(indexNames))
+	for i, n := range indexNames {
+		quoted[i] = fmt.Sprintf("%q", n)
+	}
+	return testDeferredIndexBuildProviderBlock(serverURL) + fmt.Sprintf(`
+resource "capellaextras_deferred_index_build" "test" {
+  organization_id = %[1]q
+  project_id      = %[2]q
+  cluster_id      = %[3]q
+  bucket_name     = %[4]q
+  index_names     = [%[5]s]
+  wait_for_ready  = true
+  timeout         = %[6]q
+}
+`, orgID, projID, clusterID, bucket, strings.Join(quoted, ", "), timeout)
+}
+
+func testDeferredIndexBuildConfigWithBatchSize(serverURL string, indexNames []string, maxPerBuild int) string {
+	quoted := make([]string, len(indexNames))
+	for i, n := range indexNames {
+		quoted[i] = fmt.Sprintf("%q", n)
+	}
+	return testDeferredIndexBuildProviderBlock(serverURL) + fmt.Sprintf(`
+resource "capellaextras_deferred_index_build" "test" {
+  organization_id       = %[1]q
+  project_id            = %[2]q
+  cluster_id            = %[3]q
+  bucket_name           = %[4]q
+  index_names           = [%[5]s]
+  max_indexes_per_build = %[6]d
+}
+`, testOrgID, testProjID, testClusterID, testBucket, strings.Join(quoted, ", "), maxPerBuild)
+}
+
 const (
 	testOrgID     = "test-org-id"
 	testProjID    = "test-proj-id"
@@ -204,7 +576,7 @@ func TestAccDeferredIndexBuildResource_allCreated(t *testing.T) {
 					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "index_statuses.idx1", "Building"),
 					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "index_statuses.idx2", "Building"),
 					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "id",
-						fmt.Sprintf("%s/%s/%s/%s", testOrgID, testProjID, testClusterID, testBucket)),
+						fmt.Sprintf("%s/%s/%s/%s/_default/_default", testOrgID, testProjID, testClusterID, testBucket)),
 					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "build_trigger_statuses.#", "1"),
 					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "build_trigger_statuses.0", "Created"),
 				),
@@ -488,6 +860,486 @@ resource "capellaextras_deferred_index_build" "test" {
 					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "scope_name", "my-scope"),
 					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "collection_name", "my-collection"),
 					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "index_statuses.idx1", "Online"),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "id",
+						fmt.Sprintf("%s/%s/%s/%s/my-scope/my-collection", testOrgID, testProjID, testClusterID, testBucket)),
+				),
+			},
+			// Moving the resource to another collection must plan the new id rather than
+			// reusing the one in state.
+			{
+				Config: testDeferredIndexBuildProviderBlock(mockSrv.URL) + fmt.Sprintf(`
+resource "capellaextras_deferred_index_build" "test" {
+  organization_id = %[1]q
+  project_id      = %[2]q
+  cluster_id      = %[3]q
+  bucket_name     = %[4]q
+  scope_name      = "my-scope"
+  collection_name = "other-collection"
+  index_names     = ["idx1"]
+}
+`, testOrgID, testProjID, testClusterID, testBucket),
+				Check: resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "id",
+					fmt.Sprintf("%s/%s/%s/%s/my-scope/other-collection", testOrgID, testProjID, testClusterID, testBucket)),
+			},
+		},
+	})
+}
+
+// TestAccDeferredIndexBuildResource_waitForReady verifies that with wait_for_ready set, the
+// apply blocks until triggered builds finish and the final status is recorded in state.
+func TestAccDeferredIndexBuildResource_waitForReady(t *testing.T) {
+	mockSrv, mock := newMockIndexServer(map[string]string{
+		"idx1": "Created",
+		"idx2": "Created",
+	})
+	mock.setBuildingResolvesTo("Ready")
+	defer mockSrv.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testDeferredIndexBuildConfigWithWait(
+					mockSrv.URL, testOrgID, testProjID, testClusterID, testBucket,
+					[]string{"idx1", "idx2"}, "1m",
+				),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "wait_for_ready", "true"),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "ready_statuses.0", "Ready"),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "index_statuses.idx1", "Ready"),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "index_statuses.idx2", "Ready"),
+				),
+			},
+		},
+	})
+
+	if got := mock.getBuildCallCount(); got != 1 {
+		t.Errorf("expected 1 build API call, got %d", got)
+	}
+}
+
+// TestAccDeferredIndexBuildResource_waitForReadyBuildError verifies that an index entering
+// an error status while waiting fails the apply with a diagnostic naming the index.
+func TestAccDeferredIndexBuildResource_waitForReadyBuildError(t *testing.T) {
+	mockSrv, mock := newMockIndexServer(map[string]string{
+		"idx1": "Created",
+	})
+	mock.setBuildingResolvesTo("Error")
+	defer mockSrv.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testDeferredIndexBuildConfigWithWait(
+					mockSrv.URL, testOrgID, testProjID, testClusterID, testBucket,
+					[]string{"idx1"}, "1m",
+				),
+				ExpectError: regexp.MustCompile(`Index "idx1" entered status "Error"`),
+			},
+		},
+	})
+}
+
+// TestAccDeferredIndexBuildResource_waitForReadyTimeout verifies that the apply fails once
+// the configured timeout elapses with builds still in progress.
+func TestAccDeferredIndexBuildResource_waitForReadyTimeout(t *testing.T) {
+	mockSrv, _ := newMockIndexServer(map[string]string{
+		"idx1": "Created",
+	})
+	defer mockSrv.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testDeferredIndexBuildConfigWithWait(
+					mockSrv.URL, testOrgID, testProjID, testClusterID, testBucket,
+					[]string{"idx1"}, "2s",
+				),
+				ExpectError: regexp.MustCompile(`Timed Out Waiting For Index Build`),
+			},
+		},
+	})
+}
+
+// TestAccDeferredIndexBuildResource_invalidTimeout verifies that a malformed or non-positive
+// timeout is rejected at plan time.
+func TestAccDeferredIndexBuildResource_invalidTimeout(t *testing.T) {
+	mockSrv, _ := newMockIndexServer(map[string]string{
+		"idx1": "Created",
+	})
+	defer mockSrv.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testDeferredIndexBuildConfigWithWait(
+					mockSrv.URL, testOrgID, testProjID, testClusterID, testBucket,
+					[]string{"idx1"}, "soon",
+				),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Timeout`),
+			},
+			{
+				Config: testDeferredIndexBuildConfigWithWait(
+					mockSrv.URL, testOrgID, testProjID, testClusterID, testBucket,
+					[]string{"idx1"}, "-5m",
+				),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`positive duration`),
+			},
+		},
+	})
+}
+
+// TestAccDeferredIndexBuildResource_providerDefaultIDs verifies that organization_id and
+// project_id fall back to the provider defaults, and that a missing value is reported at plan time.
+func TestAccDeferredIndexBuildResource_providerDefaultIDs(t *testing.T) {
+	mockSrv, _ := newMockIndexServer(map[string]string{
+		"idx1": "Online",
+	})
+	defer mockSrv.Close()
+
+	t.Setenv("CAPELLA_ORGANIZATION_ID", "")
+	t.Setenv("CAPELLA_PROJECT_ID", "")
+
+	resourceBlock := fmt.Sprintf(`
+resource "capellaextras_deferred_index_build" "test" {
+  cluster_id  = %[1]q
+  bucket_name = %[2]q
+  index_names = ["idx1"]
+}
+`, testClusterID, testBucket)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testDeferredIndexBuildProviderBlock(mockSrv.URL) + resourceBlock,
+				ExpectError: regexp.MustCompile(`Missing Organization ID`),
+			},
+			{
+				Config: fmt.Sprintf(`
+provider "capellaextras" {
+  host                 = %[1]q
+  authentication_token = "test-token"
+  organization_id      = %[2]q
+  project_id           = %[3]q
+}
+`, mockSrv.URL, testOrgID, testProjID) + resourceBlock,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "organization_id", testOrgID),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "project_id", testProjID),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "index_statuses.idx1", "Online"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccDeferredIndexBuildResource_import verifies that existing indexes can be adopted with
+// an organization/project/cluster/bucket/scope/collection/index-list identifier, and that the
+// imported state matches a resource created from configuration.
+func TestAccDeferredIndexBuildResource_import(t *testing.T) {
+	mockSrv, mock := newMockIndexServer(map[string]string{
+		"idx1": "Online",
+		"idx2": "Online",
+	})
+	defer mockSrv.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testDeferredIndexBuildConfig(
+					mockSrv.URL, testOrgID, testProjID, testClusterID, testBucket,
+					[]string{"idx1", "idx2"},
+				),
+			},
+			{
+				ResourceName:      "capellaextras_deferred_index_build.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s/%s/%s/%s/_default/_default/idx1,idx2", testOrgID, testProjID, testClusterID, testBucket),
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:  "capellaextras_deferred_index_build.test",
+				ImportState:   true,
+				ImportStateId: fmt.Sprintf("%s/%s/%s/%s", testOrgID, testProjID, testClusterID, testBucket),
+				ExpectError:   regexp.MustCompile(`Unexpected Import Identifier`),
+			},
+		},
+	})
+
+	if got := mock.getBuildCallCount(); got != 0 {
+		t.Errorf("expected 0 build API calls, got %d", got)
+	}
+}
+
+// TestAccDeferredIndexBuildResource_maxIndexesPerBuild verifies that builds are split into
+// sequential BUILD INDEX statements of at most max_indexes_per_build indexes.
+func TestAccDeferredIndexBuildResource_maxIndexesPerBuild(t *testing.T) {
+	mockSrv, mock := newMockIndexServer(map[string]string{
+		"idx1": "Created",
+		"idx2": "Created",
+		"idx3": "Created",
+	})
+	defer mockSrv.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testDeferredIndexBuildConfigWithBatchSize(mockSrv.URL, []string{"idx1", "idx2", "idx3"}, 2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "index_statuses.idx3", "Building"),
+					func(_ *terraform.State) error {
+						got := mock.getBuildStatements()
+						if len(got) != 2 {
+							return fmt.Errorf("got %d BUILD INDEX statements, want 2: %v", len(got), got)
+						}
+						if names := buildIndexNames(got[0]); strings.Join(names, ",") != "idx1,idx2" {
+							return fmt.Errorf("first batch = %v, want [idx1 idx2]", names)
+						}
+						if names := buildIndexNames(got[1]); strings.Join(names, ",") != "idx3" {
+							return fmt.Errorf("second batch = %v, want [idx3]", names)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+// TestAccDeferredIndexBuildResource_batchFailure verifies that a failed batch is reported with
+// its indexes while the other batches are still submitted.
+func TestAccDeferredIndexBuildResource_batchFailure(t *testing.T) {
+	mockSrv, mock := newMockIndexServer(map[string]string{
+		"idx1": "Created",
+		"idx2": "Created",
+		"idx3": "Created",
+	})
+	defer mockSrv.Close()
+	mock.setRejectBuildOf("idx2")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testDeferredIndexBuildConfigWithBatchSize(mockSrv.URL, []string{"idx1", "idx2", "idx3"}, 1),
+				ExpectError: regexp.MustCompile(`(?s)Cannot build indexes idx2:.*Builds were started for: idx1, idx3`),
+			},
+		},
+	})
+	if got := mock.getBuildCallCount(); got != 3 {
+		t.Errorf("build calls = %d, want 3", got)
+	}
+}
+
+// TestAccDeferredIndexBuildResource_invalidMaxIndexesPerBuild verifies the config validation.
+func TestAccDeferredIndexBuildResource_invalidMaxIndexesPerBuild(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testDeferredIndexBuildConfigWithBatchSize("http://127.0.0.1:0", []string{"idx1"}, 0),
+				ExpectError: regexp.MustCompile(`Invalid Max Indexes Per Build`),
+			},
+		},
+	})
+}
+
+// TestAccDeferredIndexBuildResource_responseError verifies that an error reported in the body
+// of an accepted BUILD INDEX request fails the apply naming the offending index, while the
+// other indexes of the statement are still built.
+func TestAccDeferredIndexBuildResource_responseError(t *testing.T) {
+	mockSrv, mock := newMockIndexServer(map[string]string{
+		"idx1": "Created",
+		"idx2": "Created",
+	})
+	defer mockSrv.Close()
+	mock.setPartialBuildErrorOf("idx2")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testDeferredIndexBuildConfig(mockSrv.URL, testOrgID, testProjID, testClusterID, testBucket, []string{"idx1", "idx2"}),
+				ExpectError: regexp.MustCompile(`(?s)Cannot build indexes idx2:.*already being built.*Builds were started for: idx1`),
+			},
+			{
+				// Once the error clears, the next apply builds only the index that failed.
+				PreConfig: func() { mock.setPartialBuildErrorOf("") },
+				Config:    testDeferredIndexBuildConfig(mockSrv.URL, testOrgID, testProjID, testClusterID, testBucket, []string{"idx1", "idx2"}),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "index_statuses.idx1", "Building"),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "index_statuses.idx2", "Building"),
+					func(_ *terraform.State) error {
+						got := mock.getBuildStatements()
+						if names := buildIndexNames(got[len(got)-1]); strings.Join(names, ",") != "idx2" {
+							return fmt.Errorf("last BUILD INDEX statement built %v, want [idx2]", names)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func testDeferredIndexBuildConfigWithSelection(serverURL, selection string) string {
+	return testDeferredIndexBuildProviderBlock(serverURL) + fmt.Sprintf(`
+resource "capellaextras_deferred_index_build" "test" {
+  organization_id = %[1]q
+  project_id      = %[2]q
+  cluster_id      = %[3]q
+  bucket_name     = %[4]q
+  %[5]s
+}
+`, testOrgID, testProjID, testClusterID, testBucket, selection)
+}
+
+// TestAccDeferredIndexBuildResource_discoverAll verifies that discover_all builds every deferred
+// index in the keyspace, records the discovered names, and picks up indexes created later.
+func TestAccDeferredIndexBuildResource_discoverAll(t *testing.T) {
+	mockSrv, mock := newMockIndexServer(map[string]string{
+		"idx1": "Created",
+		"idx2": "Online",
+	})
+	defer mockSrv.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testDeferredIndexBuildConfigWithSelection(mockSrv.URL, `discover_all = true`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("capellaextras_deferred_index_build.test", "index_names"),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "discovered_index_names.#", "2"),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "discovered_index_names.0", "idx1"),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "discovered_index_names.1", "idx2"),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "index_statuses.idx1", "Building"),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "index_statuses.idx2", "Online"),
+				),
+			},
+			{
+				// A deferred index created outside Terraform is discovered and built on the next apply.
+				PreConfig: func() { mock.setStatus("idx3", "Created") },
+				Config:    testDeferredIndexBuildConfigWithSelection(mockSrv.URL, `discover_all = true`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "discovered_index_names.#", "3"),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "index_statuses.idx3", "Building"),
+				),
+			},
+		},
+	})
+
+	if got := mock.getBuildCallCount(); got != 2 {
+		t.Errorf("expected 2 build API calls, got %d", got)
+	}
+}
+
+// TestAccDeferredIndexBuildResource_indexNameRegex verifies that only indexes matching
+// index_name_regex are managed.
+func TestAccDeferredIndexBuildResource_indexNameRegex(t *testing.T) {
+	mockSrv, mock := newMockIndexServer(map[string]string{
+		"orders_by_date": "Created",
+		"orders_by_user": "Created",
+		"users_by_email": "Created",
+	})
+	defer mockSrv.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testDeferredIndexBuildConfigWithSelection(mockSrv.URL, `index_name_regex = "^orders_"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "discovered_index_names.#", "2"),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "index_statuses.%", "2"),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "index_statuses.orders_by_date", "Building"),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "index_statuses.orders_by_user", "Building"),
+					func(_ *terraform.State) error {
+						want := fmt.Sprintf("BUILD INDEX ON `%s`.`_default`.`_default`(`orders_by_date`, `orders_by_user`)", testBucket)
+						if got := mock.getBuildStatements(); len(got) != 1 || got[0] != want {
+							return fmt.Errorf("BUILD INDEX statements = %q, want [%q]", got, want)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+// TestAccDeferredIndexBuildResource_invalidIndexSelection verifies that exactly one way of
+// selecting indexes must be configured, and that the regex must compile.
+func TestAccDeferredIndexBuildResource_invalidIndexSelection(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testDeferredIndexBuildConfigWithSelection("http://127.0.0.1:0", ``),
+				ExpectError: regexp.MustCompile(`Invalid Index Selection`),
+			},
+			{
+				Config: testDeferredIndexBuildConfigWithSelection("http://127.0.0.1:0", `
+  index_names  = ["idx1"]
+  discover_all = true`),
+				ExpectError: regexp.MustCompile(`Invalid Index Selection`),
+			},
+			{
+				Config:      testDeferredIndexBuildConfigWithSelection("http://127.0.0.1:0", `index_name_regex = "("`),
+				ExpectError: regexp.MustCompile(`Invalid Index Name Regex`),
+			},
+		},
+	})
+}
+
+// TestAccDeferredIndexBuildResource_indexDetails verifies that the indexes attribute records the
+// details reported by the API, and that a triggered build clears the progress it no longer knows.
+func TestAccDeferredIndexBuildResource_indexDetails(t *testing.T) {
+	mockSrv, mock := newMockIndexServer(map[string]string{
+		"idx1": "Ready",
+		"idx2": "Created",
+	})
+	defer mockSrv.Close()
+	progress, replicas := int64(100), int64(1)
+	mock.setStatusDetails("idx1", indexes.IndexBuildStatusResponse{
+		Progress:   &progress,
+		NumReplica: &replicas,
+		Hosts:      []string{"node1:9102", "node2:9102"},
+	})
+	mock.setStatusDetails("idx2", indexes.IndexBuildStatusResponse{
+		NumReplica: &replicas,
+		Error:      "previous build was interrupted",
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testDeferredIndexBuildConfig(
+					mockSrv.URL, testOrgID, testProjID, testClusterID, testBucket,
+					[]string{"idx1", "idx2"},
+				),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "indexes.%", "2"),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "indexes.idx1.status", "Ready"),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "indexes.idx1.progress", "100"),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "indexes.idx1.num_replica", "1"),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "indexes.idx1.hosts.#", "2"),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "indexes.idx1.hosts.0", "node1:9102"),
+					resource.TestCheckNoResourceAttr("capellaextras_deferred_index_build.test", "indexes.idx1.error"),
+					resource.TestMatchResourceAttr("capellaextras_deferred_index_build.test", "indexes.idx1.last_checked",
+						regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$`)),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "indexes.idx2.status", "Building"),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "indexes.idx2.num_replica", "1"),
+					resource.TestCheckNoResourceAttr("capellaextras_deferred_index_build.test", "indexes.idx2.progress"),
+					resource.TestCheckNoResourceAttr("capellaextras_deferred_index_build.test", "indexes.idx2.error"),
 				),
 			},
 		},
@@ -0,0 +1,143 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func testDeferredIndexBuildsConfig(serverURL, keyspaces string) string {
+	return testDeferredIndexBuildProviderBlock(serverURL) + fmt.Sprintf(`
+resource "capellaextras_deferred_index_builds" "test" {
+  organization_id = %[1]q
+  project_id      = %[2]q
+  cluster_id      = %[3]q
+  keyspaces       = %[4]s
+}
+`, testOrgID, testProjID, testClusterID, keyspaces)
+}
+
+// TestAccDeferredIndexBuildsResource_multipleKeyspaces verifies that each keyspace is built with
+// its own BUILD INDEX statement and that all statuses are combined into index_statuses.
+func TestAccDeferredIndexBuildsResource_multipleKeyspaces(t *testing.T) {
+	mockSrv, mock := newMockIndexServer(map[string]string{
+		"route_idx":   "Created",
+		"airline_idx": "Created",
+		"hotel_idx":   "Online",
+	})
+	defer mockSrv.Close()
+
+	keyspaces := fmt.Sprintf(`[
+    {
+      bucket_name     = %[1]q
+      scope_name      = "inventory"
+      collection_name = "route"
+      index_names     = ["route_idx"]
+    },
+    {
+      bucket_name     = %[1]q
+      scope_name      = "inventory"
+      collection_name = "airline"
+      index_names     = ["airline_idx"]
+    },
+    {
+      bucket_name = %[1]q
+      index_names = ["hotel_idx"]
+    },
+  ]`, testBucket)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testDeferredIndexBuildsConfig(mockSrv.URL, keyspaces),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("capellaextras_deferred_index_builds.test", "id",
+						regexp.MustCompile(fmt.Sprintf(`^%s/%s/%s/keyspaces/[0-9a-f]{16}$`, testOrgID, testProjID, testClusterID))),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_builds.test", "index_statuses.%", "3"),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_builds.test",
+						"index_statuses."+testBucket+"/inventory/route/route_idx", "Building"),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_builds.test",
+						"index_statuses."+testBucket+"/inventory/airline/airline_idx", "Building"),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_builds.test",
+						"index_statuses."+testBucket+"/_default/_default/hotel_idx", "Online"),
+					resource.TestCheckResourceAttr("capellaextras_deferred_index_builds.test",
+						"indexes."+testBucket+"/_default/_default/hotel_idx.status", "Online"),
+					func(_ *terraform.State) error {
+						want := []string{
+							fmt.Sprintf("BUILD INDEX ON `%s`.`inventory`.`route`(`route_idx`)", testBucket),
+							fmt.Sprintf("BUILD INDEX ON `%s`.`inventory`.`airline`(`airline_idx`)", testBucket),
+						}
+						if got := mock.getBuildStatements(); strings.Join(got, "\n") != strings.Join(want, "\n") {
+							return fmt.Errorf("BUILD INDEX statements = %q, want %q", got, want)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// An index recreated as deferred in one keyspace is rebuilt without touching the others.
+				PreConfig: func() { mock.setStatus("airline_idx", "Created") },
+				Config:    testDeferredIndexBuildsConfig(mockSrv.URL, keyspaces),
+				Check: resource.TestCheckResourceAttr("capellaextras_deferred_index_builds.test",
+					"index_statuses."+testBucket+"/inventory/airline/airline_idx", "Building"),
+			},
+		},
+	})
+
+	if got := mock.getBuildCallCount(); got != 3 {
+		t.Errorf("expected 3 build API calls, got %d", got)
+	}
+}
+
+// TestAccDeferredIndexBuildsResource_keyspaceFailure verifies that a keyspace whose build fails
+// is reported without stopping the builds of the other keyspaces.
+func TestAccDeferredIndexBuildsResource_keyspaceFailure(t *testing.T) {
+	mockSrv, mock := newMockIndexServer(map[string]string{
+		"idx1": "Created",
+		"idx2": "Created",
+	})
+	defer mockSrv.Close()
+	mock.setRejectBuildOf("idx1")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testDeferredIndexBuildsConfig(mockSrv.URL, fmt.Sprintf(`[
+    { bucket_name = %[1]q, collection_name = "a", index_names = ["idx1"] },
+    { bucket_name = %[1]q, collection_name = "b", index_names = ["idx2"] },
+  ]`, testBucket)),
+				ExpectError: regexp.MustCompile(`Keyspace ` + testBucket + `/_default/a: Cannot build indexes idx1`),
+			},
+		},
+	})
+
+	if got := mock.getBuildCallCount(); got != 2 {
+		t.Errorf("expected 2 build API calls, got %d", got)
+	}
+}
+
+// TestAccDeferredIndexBuildsResource_duplicateKeyspace verifies that listing a keyspace twice
+// is rejected at validation.
+func TestAccDeferredIndexBuildsResource_duplicateKeyspace(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testDeferredIndexBuildsConfig("http://127.0.0.1:0", fmt.Sprintf(`[
+    { bucket_name = %[1]q, index_names = ["idx1"] },
+    { bucket_name = %[1]q, scope_name = "_default", index_names = ["idx2"] },
+  ]`, testBucket)),
+				ExpectError: regexp.MustCompile(`Duplicate Keyspace`),
+			},
+		},
+	})
+}
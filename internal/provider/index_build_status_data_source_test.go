@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testIndexBuildStatusDataSourceConfig(serverURL, indexName string) string {
+	return testDeferredIndexBuildProviderBlock(serverURL) + fmt.Sprintf(`
+data "capellaextras_index_build_status" "test" {
+  organization_id = %[1]q
+  project_id      = %[2]q
+  cluster_id      = %[3]q
+  bucket_name     = %[4]q
+  index_name      = %[5]q
+}
+`, testOrgID, testProjID, testClusterID, testBucket, indexName)
+}
+
+// TestAccIndexBuildStatusDataSource verifies that the data source exposes the index status.
+func TestAccIndexBuildStatusDataSource(t *testing.T) {
+	mockSrv, _ := newMockIndexServer(map[string]string{
+		"idx1": "Ready",
+	})
+	defer mockSrv.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testIndexBuildStatusDataSourceConfig(mockSrv.URL, "idx1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.capellaextras_index_build_status.test", "status", "Ready"),
+					resource.TestCheckResourceAttr("data.capellaextras_index_build_status.test", "organization_id", testOrgID),
+					resource.TestCheckNoResourceAttr("data.capellaextras_index_build_status.test", "scope_name"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccIndexBuildStatusDataSource_notFound verifies that a missing index is reported clearly.
+func TestAccIndexBuildStatusDataSource_notFound(t *testing.T) {
+	mockSrv, _ := newMockIndexServer(map[string]string{})
+	defer mockSrv.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testIndexBuildStatusDataSourceConfig(mockSrv.URL, "missing"),
+				ExpectError: regexp.MustCompile(`Index Not Found`),
+			},
+		},
+	})
+}
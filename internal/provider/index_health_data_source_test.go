@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/cdsre/terraform-provider-capellaextras/api/buckets"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testIndexHealthDataSourceConfig(serverURL, keyspace string) string {
+	return testDeferredIndexBuildProviderBlock(serverURL) + fmt.Sprintf(`
+data "capellaextras_index_health" "test" {
+  organization_id = %[1]q
+  project_id      = %[2]q
+  cluster_id      = %[3]q
+  %[4]s
+}
+`, testOrgID, testProjID, testClusterID, keyspace)
+}
+
+// TestAccIndexHealthDataSource_keyspace verifies that the indexes of a keyspace are counted by status.
+func TestAccIndexHealthDataSource_keyspace(t *testing.T) {
+	mockSrv, _ := newMockIndexServer(map[string]string{
+		"idx1": "Ready",
+		"idx2": "Ready",
+		"idx3": "Created",
+		"idx4": "Building",
+		"idx5": "Error",
+		"idx6": "Offline",
+		"idx7": "Scheduled for Creation",
+	})
+	defer mockSrv.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testIndexHealthDataSourceConfig(mockSrv.URL, fmt.Sprintf("bucket_name = %q", testBucket)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.capellaextras_index_health.test", "total", "7"),
+					resource.TestCheckResourceAttr("data.capellaextras_index_health.test", "ready", "2"),
+					resource.TestCheckResourceAttr("data.capellaextras_index_health.test", "deferred", "1"),
+					resource.TestCheckResourceAttr("data.capellaextras_index_health.test", "building", "1"),
+					resource.TestCheckResourceAttr("data.capellaextras_index_health.test", "error", "1"),
+					resource.TestCheckResourceAttr("data.capellaextras_index_health.test", "offline", "1"),
+					resource.TestCheckResourceAttr("data.capellaextras_index_health.test", "other", "1"),
+					resource.TestCheckResourceAttr("data.capellaextras_index_health.test", "all_ready", "false"),
+					resource.TestCheckResourceAttr("data.capellaextras_index_health.test", "not_ready_indexes.%", "5"),
+					resource.TestCheckResourceAttr("data.capellaextras_index_health.test",
+						"not_ready_indexes."+testBucket+"/_default/_default/idx3", "Created"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccIndexHealthDataSource_cluster verifies that every collection of every bucket is counted
+// when no bucket is given.
+func TestAccIndexHealthDataSource_cluster(t *testing.T) {
+	mockSrv, mock := newMockIndexServer(map[string]string{
+		"idx1": "Ready",
+		"idx2": "Created",
+	})
+	defer mockSrv.Close()
+	mock.setBuckets(map[string][]buckets.Scope{
+		testBucket: {{Name: "inventory", Collections: []buckets.Collection{{Name: "route"}, {Name: "airline"}}}},
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testIndexHealthDataSourceConfig(mockSrv.URL, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.capellaextras_index_health.test", "total", "4"),
+					resource.TestCheckResourceAttr("data.capellaextras_index_health.test", "ready", "2"),
+					resource.TestCheckResourceAttr("data.capellaextras_index_health.test", "deferred", "2"),
+					resource.TestCheckResourceAttr("data.capellaextras_index_health.test", "all_ready", "false"),
+					resource.TestCheckResourceAttr("data.capellaextras_index_health.test",
+						"not_ready_indexes."+testBucket+"/inventory/route/idx2", "Created"),
+					resource.TestCheckResourceAttr("data.capellaextras_index_health.test",
+						"not_ready_indexes."+testBucket+"/inventory/airline/idx2", "Created"),
+				),
+			},
+			{
+				PreConfig: func() { mock.setStatus("idx2", "Ready") },
+				Config:    testIndexHealthDataSourceConfig(mockSrv.URL, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.capellaextras_index_health.test", "all_ready", "true"),
+					resource.TestCheckResourceAttr("data.capellaextras_index_health.test", "not_ready_indexes.%", "0"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccIndexHealthDataSource_scopeWithoutBucket verifies that a scope without a bucket is rejected.
+func TestAccIndexHealthDataSource_scopeWithoutBucket(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testIndexHealthDataSourceConfig("http://127.0.0.1:0", `scope_name = "inventory"`),
+				ExpectError: regexp.MustCompile(`Missing Bucket Name`),
+			},
+		},
+	})
+}
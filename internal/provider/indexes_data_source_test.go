@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cdsre/terraform-provider-capellaextras/api/indexes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testIndexesDataSourceConfig(serverURL string) string {
+	return testDeferredIndexBuildProviderBlock(serverURL) + fmt.Sprintf(`
+data "capellaextras_indexes" "test" {
+  organization_id = %[1]q
+  project_id      = %[2]q
+  cluster_id      = %[3]q
+  bucket_name     = %[4]q
+}
+`, testOrgID, testProjID, testClusterID, testBucket)
+}
+
+// TestAccIndexesDataSource verifies that every index in the keyspace is listed with its properties.
+func TestAccIndexesDataSource(t *testing.T) {
+	mockSrv, mock := newMockIndexServer(map[string]string{
+		"#primary":       "Ready",
+		"idx_by_airline": "Created",
+	})
+	defer mockSrv.Close()
+	mock.setIndexDefinition(indexes.Index{
+		IndexName: "#primary",
+		IsPrimary: true,
+	})
+	mock.setIndexDefinition(indexes.Index{
+		IndexName:      "idx_by_airline",
+		SecondaryExprs: []string{"`airline`", "`stops`"},
+		Where:          "`stops` > 0",
+		PartitionBy:    "HASH(`airline`)",
+		NumPartition:   8,
+		NumReplica:     1,
+		DeferBuild:     true,
+	})
+
+	const ds = "data.capellaextras_indexes.test"
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testIndexesDataSourceConfig(mockSrv.URL),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(ds, "indexes.#", "2"),
+					resource.TestCheckResourceAttr(ds, "indexes.0.name", "#primary"),
+					resource.TestCheckResourceAttr(ds, "indexes.0.is_primary", "true"),
+					resource.TestCheckResourceAttr(ds, "indexes.0.keys.#", "0"),
+					resource.TestCheckNoResourceAttr(ds, "indexes.0.condition"),
+					resource.TestCheckResourceAttr(ds, "indexes.0.state", "Ready"),
+					resource.TestCheckResourceAttr(ds, "indexes.1.name", "idx_by_airline"),
+					resource.TestCheckResourceAttrSet(ds, "indexes.1.definition"),
+					resource.TestCheckResourceAttr(ds, "indexes.1.keys.#", "2"),
+					resource.TestCheckResourceAttr(ds, "indexes.1.keys.0", "`airline`"),
+					resource.TestCheckResourceAttr(ds, "indexes.1.condition", "`stops` > 0"),
+					resource.TestCheckResourceAttr(ds, "indexes.1.partition_by", "HASH(`airline`)"),
+					resource.TestCheckResourceAttr(ds, "indexes.1.num_partition", "8"),
+					resource.TestCheckResourceAttr(ds, "indexes.1.num_replica", "1"),
+					resource.TestCheckResourceAttr(ds, "indexes.1.deferred", "true"),
+					resource.TestCheckResourceAttr(ds, "indexes.1.state", "Created"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccIndexesDataSource_empty verifies that a keyspace without indexes yields an empty list.
+func TestAccIndexesDataSource_empty(t *testing.T) {
+	mockSrv, _ := newMockIndexServer(map[string]string{})
+	defer mockSrv.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testIndexesDataSourceConfig(mockSrv.URL),
+				Check:  resource.TestCheckResourceAttr("data.capellaextras_indexes.test", "indexes.#", "0"),
+			},
+		},
+	})
+}
@@ -0,0 +1,124 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+// TestAccParseIDFunction verifies that slash-separated IDs of this provider and composite IDs of
+// the Capella provider are split into the same components.
+func TestAccParseIDFunction(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testDeferredIndexBuildProviderBlock("http://127.0.0.1:0") + `
+locals {
+  keyspace  = provider::capellaextras::parse_id("org/proj/cluster/travel-sample/inventory/route")
+  index     = provider::capellaextras::parse_id("org/proj/cluster/travel-sample/_default/_default/idx1")
+  builds    = provider::capellaextras::parse_id("org/proj/cluster/keyspaces/0123456789abcdef")
+  upstream  = provider::capellaextras::parse_id("bucket_id=dHJhdmVsLXNhbXBsZQ==,cluster_id=cluster,project_id=proj,organization_id=org")
+  json_like = provider::capellaextras::parse_id(jsonencode({ organization_id = "org", project_id = "proj", cluster_id = "cluster" }))
+}
+
+output "keyspace_collection" {
+  value = local.keyspace.collection_name
+}
+
+output "keyspace_index_is_null" {
+  value = local.keyspace.index_name == null
+}
+
+output "index_name" {
+  value = local.index.index_name
+}
+
+output "builds_cluster" {
+  value = local.builds.cluster_id
+}
+
+output "builds_bucket_is_null" {
+  value = local.builds.bucket_name == null
+}
+
+output "upstream_bucket" {
+  value = local.upstream.bucket_name
+}
+
+output "upstream_org" {
+  value = local.upstream.organization_id
+}
+
+output "json_like_cluster" {
+  value = local.json_like.cluster_id
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("keyspace_collection", "route"),
+					resource.TestCheckOutput("keyspace_index_is_null", "true"),
+					resource.TestCheckOutput("index_name", "idx1"),
+					resource.TestCheckOutput("builds_cluster", "cluster"),
+					resource.TestCheckOutput("builds_bucket_is_null", "true"),
+					resource.TestCheckOutput("upstream_bucket", "travel-sample"),
+					resource.TestCheckOutput("upstream_org", "org"),
+					resource.TestCheckOutput("json_like_cluster", "cluster"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccParseIDFunction_invalid verifies that malformed IDs are rejected.
+func TestAccParseIDFunction_invalid(t *testing.T) {
+	testCases := map[string]struct {
+		id        string
+		wantError string
+	}{
+		"too few components": {
+			id:        "org/proj",
+			wantError: `expected 4 to 7 slash-separated components, got 2`,
+		},
+		"five components without keyspace digest": {
+			id:        "org/proj/cluster/bucket/scope",
+			wantError: `expected a 5 component ID of the form`,
+		},
+		"empty component": {
+			id:        "org//cluster/bucket",
+			wantError: `component 2 \(project_id\) is empty`,
+		},
+		"unsupported key": {
+			id:        "cluster_id=cluster,region=us-east-1",
+			wantError: `unsupported key "region"`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			resource.UnitTest(t, resource.TestCase{
+				TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+					tfversion.SkipBelow(tfversion.Version1_8_0),
+				},
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config: testDeferredIndexBuildProviderBlock("http://127.0.0.1:0") + `
+output "test" {
+  value = provider::capellaextras::parse_id("` + tc.id + `")
+}
+`,
+						ExpectError: regexp.MustCompile(tc.wantError),
+					},
+				},
+			})
+		})
+	}
+}
@@ -5,14 +5,19 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"time"
 
 	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
 	"github.com/cdsre/terraform-provider-capellaextras/internal/actions"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/datasources"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/ephemeralresources"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/functions"
 	"github.com/cdsre/terraform-provider-capellaextras/internal/resources"
 	"github.com/hashicorp/terraform-plugin-framework/action"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -31,6 +36,15 @@ var _ provider.ProviderWithActions = &CapellaProvider{}
 const (
 	capellaAuthenticationTokenField = "authentication_token"
 	capellaPublicAPIHostField       = "host"
+	capellaAPIKeyField              = "api_key"
+	capellaAPISecretField           = "api_secret"
+	organizationIDField             = "organization_id"
+	projectIDField                  = "project_id"
+	maxRetriesField                 = "max_retries"
+	retryWaitMinField               = "retry_wait_min"
+	retryWaitMaxField               = "retry_wait_max"
+	requestsPerSecondField          = "requests_per_second"
+	maxConcurrentRequestsField      = "max_concurrent_requests"
 	apiRequestTimeout               = 60 * time.Second
 	defaultAPIHostURL               = "https://cloudapi.cloud.couchbase.com"
 	providerName                    = "couchbase-capella"
@@ -46,8 +60,17 @@ type CapellaProvider struct {
 
 // CapellaProviderModel describes the provider data model.
 type CapellaProviderModel struct {
-	Host                types.String `tfsdk:"host"`
-	AuthenticationToken types.String `tfsdk:"authentication_token"`
+	Host                  types.String  `tfsdk:"host"`
+	AuthenticationToken   types.String  `tfsdk:"authentication_token"`
+	APIKey                types.String  `tfsdk:"api_key"`
+	APISecret             types.String  `tfsdk:"api_secret"`
+	OrganizationID        types.String  `tfsdk:"organization_id"`
+	ProjectID             types.String  `tfsdk:"project_id"`
+	MaxRetries            types.Int64   `tfsdk:"max_retries"`
+	RetryWaitMin          types.String  `tfsdk:"retry_wait_min"`
+	RetryWaitMax          types.String  `tfsdk:"retry_wait_max"`
+	RequestsPerSecond     types.Float64 `tfsdk:"requests_per_second"`
+	MaxConcurrentRequests types.Int64   `tfsdk:"max_concurrent_requests"`
 }
 
 func (p *CapellaProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -65,7 +88,48 @@ func (p *CapellaProvider) Schema(ctx context.Context, req provider.SchemaRequest
 			"authentication_token": schema.StringAttribute{
 				Optional:    true,
 				Sensitive:   true,
-				Description: "Capella API Token that serves as an authentication mechanism.",
+				Description: "Capella API Token that serves as an authentication mechanism. Conflicts with `api_key` and `api_secret`.",
+			},
+			"api_key": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Capella API key ID, used together with `api_secret` as an alternative to `authentication_token`.",
+			},
+			"api_secret": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Capella API key secret, used together with `api_key` as an alternative to `authentication_token`.",
+			},
+			"organization_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "Default organization ID for resources, data sources and actions that do not set `organization_id` themselves.",
+			},
+			"project_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "Default project ID for resources, data sources and actions that do not set `project_id` themselves.",
+			},
+			"max_retries": schema.Int64Attribute{
+				Optional: true,
+				Description: fmt.Sprintf("Maximum number of times a failed API request is retried. Rate-limited (429) requests are "+
+					"retried for any method; server errors are only retried for idempotent methods. Defaults to %d.", apiclient.DefaultRetryMax),
+			},
+			"retry_wait_min": schema.StringAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("Minimum wait between retries as a Go duration string (e.g. `500ms`). Defaults to `%s`.", apiclient.DefaultRetryWaitMin),
+			},
+			"retry_wait_max": schema.StringAttribute{
+				Optional: true,
+				Description: fmt.Sprintf("Maximum wait between retries as a Go duration string (e.g. `30s`). Waits requested by the API "+
+					"through `Retry-After` or rate-limit headers are honoured up to `%s`. Defaults to `%s`.", apiclient.MaxServerRetryWait, apiclient.DefaultRetryWaitMax),
+			},
+			"requests_per_second": schema.Float64Attribute{
+				Optional: true,
+				Description: "Maximum number of API requests per second sent by this provider instance, shared by all resources, " +
+					"data sources and actions. Use this to stay under the organization's Capella API quota. Unlimited when unset or `0`.",
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of API requests in flight at once, shared by all resources, data sources and actions. Unlimited when unset or `0`.",
 			},
 		},
 	}
@@ -89,8 +153,38 @@ func (p *CapellaProvider) Configure(ctx context.Context, req provider.ConfigureR
 		}
 	}
 
-	if config.AuthenticationToken.IsNull() {
+	if config.OrganizationID.IsNull() {
+		config.OrganizationID = types.StringValue(os.Getenv("CAPELLA_ORGANIZATION_ID"))
+	}
+
+	if config.ProjectID.IsNull() {
+		config.ProjectID = types.StringValue(os.Getenv("CAPELLA_PROJECT_ID"))
+	}
+
+	if config.OrganizationID.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(organizationIDField),
+			"Unknown Capella Organization ID",
+			"The provider cannot create the Capella API client as there is an unknown configuration value for the default organization ID. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the CAPELLA_ORGANIZATION_ID environment variable.",
+		)
+	}
+
+	if config.ProjectID.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(projectIDField),
+			"Unknown Capella Project ID",
+			"The provider cannot create the Capella API client as there is an unknown configuration value for the default project ID. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the CAPELLA_PROJECT_ID environment variable.",
+		)
+	}
+
+	// Credentials are only read from the environment when none are set in the configuration,
+	// so an auth mode chosen in the configuration is never mixed with one from the environment.
+	if config.AuthenticationToken.IsNull() && config.APIKey.IsNull() && config.APISecret.IsNull() {
 		config.AuthenticationToken = types.StringValue(os.Getenv("CAPELLA_AUTHENTICATION_TOKEN"))
+		config.APIKey = types.StringValue(os.Getenv("CAPELLA_API_KEY"))
+		config.APISecret = types.StringValue(os.Getenv("CAPELLA_API_SECRET"))
 	}
 
 	if config.AuthenticationToken.IsUnknown() {
@@ -102,14 +196,57 @@ func (p *CapellaProvider) Configure(ctx context.Context, req provider.ConfigureR
 		)
 	}
 
+	if config.APIKey.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(capellaAPIKeyField),
+			"Unknown Capella API Key",
+			"The provider cannot create the Capella API client as there is an unknown configuration value for the capella API key. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the CAPELLA_API_KEY environment variable.",
+		)
+	}
+
+	if config.APISecret.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(capellaAPISecretField),
+			"Unknown Capella API Secret",
+			"The provider cannot create the Capella API client as there is an unknown configuration value for the capella API secret. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the CAPELLA_API_SECRET environment variable.",
+		)
+	}
+
+	// Unknown retry and throttling settings would otherwise read as zero, silently disabling
+	// retries or throttling.
+	for _, setting := range []struct {
+		field   string
+		unknown bool
+	}{
+		{maxRetriesField, config.MaxRetries.IsUnknown()},
+		{retryWaitMinField, config.RetryWaitMin.IsUnknown()},
+		{retryWaitMaxField, config.RetryWaitMax.IsUnknown()},
+		{requestsPerSecondField, config.RequestsPerSecond.IsUnknown()},
+		{maxConcurrentRequestsField, config.MaxConcurrentRequests.IsUnknown()},
+	} {
+		if setting.unknown {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(setting.field),
+				"Unknown Capella Client Setting",
+				fmt.Sprintf("The provider cannot create the Capella API client as there is an unknown configuration value for %s. "+
+					"Either target apply the source of the value first, or set the value statically in the configuration.", setting.field),
+			)
+		}
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Set the host and authentication token to be used
+	// Set the host and credentials to be used
 
 	host := config.Host.ValueString()
 	authenticationToken := config.AuthenticationToken.ValueString()
+	apiKey := config.APIKey.ValueString()
+	apiSecret := config.APISecret.ValueString()
+	useAPIKey := apiKey != "" || apiSecret != ""
 
 	// If any of the expected configurations are missing, return
 	// error with provider-specific guidance.
@@ -123,16 +260,76 @@ func (p *CapellaProvider) Configure(ctx context.Context, req provider.ConfigureR
 		)
 	}
 
-	if authenticationToken == "" {
+	switch {
+	case authenticationToken != "" && useAPIKey:
+		resp.Diagnostics.AddAttributeError(
+			path.Root(capellaAuthenticationTokenField),
+			"Conflicting Capella Authentication",
+			"The provider cannot create the Capella API client as both an authentication token and an API key/secret are configured. "+
+				"Set either authentication_token (CAPELLA_AUTHENTICATION_TOKEN) or api_key and api_secret (CAPELLA_API_KEY and CAPELLA_API_SECRET), not both.",
+		)
+	case useAPIKey && apiKey == "":
+		resp.Diagnostics.AddAttributeError(
+			path.Root(capellaAPIKeyField),
+			"Missing Capella API Key",
+			"The provider cannot create the Capella API client as api_secret is set but api_key is missing or empty. "+
+				"Set the api_key value in the configuration or use the CAPELLA_API_KEY environment variable.",
+		)
+	case useAPIKey && apiSecret == "":
+		resp.Diagnostics.AddAttributeError(
+			path.Root(capellaAPISecretField),
+			"Missing Capella API Secret",
+			"The provider cannot create the Capella API client as api_key is set but api_secret is missing or empty. "+
+				"Set the api_secret value in the configuration or use the CAPELLA_API_SECRET environment variable.",
+		)
+	case authenticationToken == "" && !useAPIKey:
 		resp.Diagnostics.AddAttributeError(
 			path.Root(capellaAuthenticationTokenField),
 			"Missing Capella Authentication Token",
 			"The provider cannot create the Capella API client as there is a missing or empty value for the capella authentication token. "+
-				"Set the password value in the configuration or use the CAPELLA_AUTHENTICATION_TOKEN environment variable. "+
+				"Set the authentication_token value in the configuration or use the CAPELLA_AUTHENTICATION_TOKEN environment variable, "+
+				"or authenticate with api_key and api_secret (CAPELLA_API_KEY and CAPELLA_API_SECRET) instead. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
 
+	retryMax := apiclient.DefaultRetryMax
+	if !config.MaxRetries.IsNull() {
+		retryMax = int(config.MaxRetries.ValueInt64())
+		if retryMax < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(maxRetriesField),
+				"Invalid Max Retries",
+				"The provider cannot create the Capella API client as max_retries must not be negative.",
+			)
+		}
+	}
+	requestsPerSecond := config.RequestsPerSecond.ValueFloat64()
+	if requestsPerSecond < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(requestsPerSecondField),
+			"Invalid Requests Per Second",
+			"The provider cannot create the Capella API client as requests_per_second must not be negative.",
+		)
+	}
+	maxConcurrentRequests := int(config.MaxConcurrentRequests.ValueInt64())
+	if maxConcurrentRequests < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(maxConcurrentRequestsField),
+			"Invalid Max Concurrent Requests",
+			"The provider cannot create the Capella API client as max_concurrent_requests must not be negative.",
+		)
+	}
+	retryWaitMin := parseDurationAttribute(config.RetryWaitMin, retryWaitMinField, apiclient.DefaultRetryWaitMin, &resp.Diagnostics)
+	retryWaitMax := parseDurationAttribute(config.RetryWaitMax, retryWaitMaxField, apiclient.DefaultRetryWaitMax, &resp.Diagnostics)
+	if retryWaitMin > retryWaitMax {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(retryWaitMinField),
+			"Invalid Retry Wait",
+			fmt.Sprintf("The provider cannot create the Capella API client as retry_wait_min (%s) is greater than retry_wait_max (%s).", retryWaitMin, retryWaitMax),
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -140,32 +337,73 @@ func (p *CapellaProvider) Configure(ctx context.Context, req provider.ConfigureR
 	// Configuration values are now available.
 	// if data.Endpoint.IsNull() { /* ... */ }
 
+	var auth apiclient.Authenticator = apiclient.BearerTokenAuth{Token: authenticationToken}
+	if useAPIKey {
+		auth = apiclient.APIKeySecretAuth{Key: apiKey, Secret: apiSecret}
+	}
+
 	// Example client configuration for data sources and resources
 	client := apiclient.NewClient(
 		apiclient.WithBaseURL(config.Host.ValueString()),
-		apiclient.WithAuthenticator(apiclient.BearerTokenAuth{Token: config.AuthenticationToken.ValueString()}),
+		apiclient.WithAuthenticator(auth),
+		apiclient.WithOrgID(config.OrganizationID.ValueString()),
+		apiclient.WithProjectID(config.ProjectID.ValueString()),
+		apiclient.WithRetryMax(retryMax),
+		apiclient.WithRetryWait(retryWaitMin, retryWaitMax),
+		apiclient.WithRateLimiter(apiclient.NewRateLimiter(requestsPerSecond, maxConcurrentRequests)),
 	)
 	resp.DataSourceData = client
 	resp.ResourceData = client
 	resp.ActionData = client
+	resp.EphemeralResourceData = client
+}
+
+// parseDurationAttribute parses an optional duration attribute, returning def when it is null.
+func parseDurationAttribute(v types.String, field string, def time.Duration, diags *diag.Diagnostics) time.Duration {
+	if v.IsNull() || v.IsUnknown() {
+		return def
+	}
+	d, err := time.ParseDuration(v.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root(field),
+			"Invalid Duration",
+			fmt.Sprintf("The provider cannot create the Capella API client as %s %q is not a valid duration: %v", field, v.ValueString(), err),
+		)
+		return def
+	}
+	return d
 }
 
 func (p *CapellaProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		resources.NewDeferredIndexBuildResource,
+		resources.NewDeferredIndexBuildsResource,
+		resources.NewQueryIndexResource,
 	}
 }
 
 func (p *CapellaProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
-	return []func() ephemeral.EphemeralResource{}
+	return []func() ephemeral.EphemeralResource{
+		ephemeralresources.NewDatabaseCredentialEphemeralResource,
+		ephemeralresources.NewAPIKeyEphemeralResource,
+	}
 }
 
 func (p *CapellaProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		datasources.NewIndexBuildStatusDataSource,
+		datasources.NewIndexHealthDataSource,
+		datasources.NewIndexesDataSource,
+	}
 }
 
 func (p *CapellaProvider) Functions(ctx context.Context) []func() function.Function {
-	return []func() function.Function{}
+	return []func() function.Function{
+		functions.NewBuildIndexStatementFunction,
+		functions.NewCreateIndexStatementFunction,
+		functions.NewParseIDFunction,
+	}
 }
 
 func (p *CapellaProvider) Actions(ctx context.Context) []func() action.Action {
@@ -4,11 +4,14 @@
 package provider
 
 import (
+	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-testing/echoprovider"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
 // testAccProtoV6ProviderFactories is used to instantiate a provider during acceptance testing.
@@ -37,3 +40,202 @@ func testAccPreCheck(t *testing.T) {
 	// about the appropriate environment variables being set are common to see in a pre-check
 	// function.
 }
+
+// TestAccProvider_retrySettings verifies that retry and rate-limit settings are validated when the provider is configured.
+func TestAccProvider_retrySettings(t *testing.T) {
+	mockSrv, _ := newMockIndexServer(map[string]string{
+		"idx1": "Online",
+	})
+	defer mockSrv.Close()
+
+	resourceBlock := fmt.Sprintf(`
+resource "capellaextras_deferred_index_build" "test" {
+  organization_id = %[1]q
+  project_id      = %[2]q
+  cluster_id      = %[3]q
+  bucket_name     = %[4]q
+  index_names     = ["idx1"]
+}
+`, testOrgID, testProjID, testClusterID, testBucket)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "capellaextras" {
+  host                 = %[1]q
+  authentication_token = "test-token"
+  retry_wait_min       = "soon"
+}
+`, mockSrv.URL) + resourceBlock,
+				ExpectError: regexp.MustCompile(`Invalid Duration`),
+			},
+			{
+				Config: fmt.Sprintf(`
+provider "capellaextras" {
+  host                 = %[1]q
+  authentication_token = "test-token"
+  retry_wait_min       = "10s"
+  retry_wait_max       = "1s"
+}
+`, mockSrv.URL) + resourceBlock,
+				ExpectError: regexp.MustCompile(`Invalid Retry Wait`),
+			},
+			{
+				Config: fmt.Sprintf(`
+provider "capellaextras" {
+  host                 = %[1]q
+  authentication_token = "test-token"
+  requests_per_second  = -1
+}
+`, mockSrv.URL) + resourceBlock,
+				ExpectError: regexp.MustCompile(`Invalid Requests Per Second`),
+			},
+			{
+				Config: fmt.Sprintf(`
+provider "capellaextras" {
+  host                    = %[1]q
+  authentication_token    = "test-token"
+  max_retries             = 1
+  retry_wait_min          = "100ms"
+  retry_wait_max          = "1s"
+  requests_per_second     = 5
+  max_concurrent_requests = 2
+}
+`, mockSrv.URL) + resourceBlock,
+				Check: resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "index_statuses.idx1", "Online"),
+			},
+		},
+	})
+}
+
+func testProviderAuthResourceBlock() string {
+	return fmt.Sprintf(`
+resource "capellaextras_deferred_index_build" "test" {
+  organization_id = %[1]q
+  project_id      = %[2]q
+  cluster_id      = %[3]q
+  bucket_name     = %[4]q
+  index_names     = ["idx1"]
+}
+`, testOrgID, testProjID, testClusterID, testBucket)
+}
+
+// TestAccProvider_bearerTokenAuth verifies that authentication_token is sent as a bearer token.
+func TestAccProvider_bearerTokenAuth(t *testing.T) {
+	mockSrv, mock := newMockIndexServer(map[string]string{
+		"idx1": "Online",
+	})
+	mock.setRequiredHeaders(map[string]string{"Authorization": "Bearer test-token"})
+	defer mockSrv.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testDeferredIndexBuildProviderBlock(mockSrv.URL) + testProviderAuthResourceBlock(),
+				Check:  resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "index_statuses.idx1", "Online"),
+			},
+		},
+	})
+}
+
+// TestAccProvider_apiKeySecretAuth verifies that api_key and api_secret are sent as key/secret headers.
+func TestAccProvider_apiKeySecretAuth(t *testing.T) {
+	mockSrv, mock := newMockIndexServer(map[string]string{
+		"idx1": "Online",
+	})
+	mock.setRequiredHeaders(map[string]string{"X-Client-Id": "test-key", "X-Client-Secret": "test-secret"})
+	defer mockSrv.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "capellaextras" {
+  host       = %[1]q
+  api_key    = "test-key"
+  api_secret = "test-secret"
+}
+`, mockSrv.URL) + testProviderAuthResourceBlock(),
+				Check: resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "index_statuses.idx1", "Online"),
+			},
+		},
+	})
+}
+
+// TestAccProvider_apiKeySecretAuthFromEnv verifies that CAPELLA_API_KEY and CAPELLA_API_SECRET
+// are used when no credentials are set in the configuration.
+func TestAccProvider_apiKeySecretAuthFromEnv(t *testing.T) {
+	mockSrv, mock := newMockIndexServer(map[string]string{
+		"idx1": "Online",
+	})
+	mock.setRequiredHeaders(map[string]string{"X-Client-Id": "env-key", "X-Client-Secret": "env-secret"})
+	defer mockSrv.Close()
+
+	t.Setenv("CAPELLA_AUTHENTICATION_TOKEN", "")
+	t.Setenv("CAPELLA_API_KEY", "env-key")
+	t.Setenv("CAPELLA_API_SECRET", "env-secret")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "capellaextras" {
+  host = %[1]q
+}
+`, mockSrv.URL) + testProviderAuthResourceBlock(),
+				Check: resource.TestCheckResourceAttr("capellaextras_deferred_index_build.test", "index_statuses.idx1", "Online"),
+			},
+		},
+	})
+}
+
+// TestAccProvider_authValidation verifies that exactly one complete auth mode must be configured.
+func TestAccProvider_authValidation(t *testing.T) {
+	mockSrv, _ := newMockIndexServer(map[string]string{
+		"idx1": "Online",
+	})
+	defer mockSrv.Close()
+
+	t.Setenv("CAPELLA_AUTHENTICATION_TOKEN", "")
+	t.Setenv("CAPELLA_API_KEY", "")
+	t.Setenv("CAPELLA_API_SECRET", "")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "capellaextras" {
+  host                 = %[1]q
+  authentication_token = "test-token"
+  api_key              = "test-key"
+  api_secret           = "test-secret"
+}
+`, mockSrv.URL) + testProviderAuthResourceBlock(),
+				ExpectError: regexp.MustCompile(`Conflicting Capella Authentication`),
+			},
+			{
+				Config: fmt.Sprintf(`
+provider "capellaextras" {
+  host    = %[1]q
+  api_key = "test-key"
+}
+`, mockSrv.URL) + testProviderAuthResourceBlock(),
+				ExpectError: regexp.MustCompile(`Missing Capella API Secret`),
+			},
+			{
+				Config: fmt.Sprintf(`
+provider "capellaextras" {
+  host = %[1]q
+}
+`, mockSrv.URL) + testProviderAuthResourceBlock(),
+				ExpectError: regexp.MustCompile(`Missing Capella Authentication Token`),
+			},
+		},
+	})
+}
@@ -0,0 +1,323 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/cdsre/terraform-provider-capellaextras/api/indexes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testQueryIndexConfig returns a capellaextras_query_index configuration; body holds the
+// definition arguments.
+func testQueryIndexConfig(serverURL, indexName, body string) string {
+	return testDeferredIndexBuildProviderBlock(serverURL) + fmt.Sprintf(`
+resource "capellaextras_query_index" "test" {
+  organization_id = %[1]q
+  project_id      = %[2]q
+  cluster_id      = %[3]q
+  bucket_name     = %[4]q
+  index_name      = %[5]q
+%[6]s
+}
+`, testOrgID, testProjID, testClusterID, testBucket, indexName, body)
+}
+
+// TestAccQueryIndexResource_secondary verifies creating a deferred, partitioned, partial index.
+func TestAccQueryIndexResource_secondary(t *testing.T) {
+	mockSrv, mock := newMockIndexServer(map[string]string{})
+	defer mockSrv.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testQueryIndexConfig(mockSrv.URL, "idx_by_airline", `
+  index_keys   = ["airline", "DISTINCT ARRAY s.day FOR s IN schedule END"]
+  where        = "stops > 0"
+  partition_by = "HASH(meta().id)"
+  with = {
+    defer_build = true
+    num_replica = 1
+  }
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("capellaextras_query_index.test", "status", "Created"),
+					resource.TestCheckResourceAttr("capellaextras_query_index.test", "id",
+						fmt.Sprintf("%s/%s/%s/%s/_default/_default/idx_by_airline", testOrgID, testProjID, testClusterID, testBucket)),
+					func(_ *terraform.State) error {
+						want := "CREATE INDEX `idx_by_airline` ON `" + testBucket + "`.`_default`.`_default`" +
+							"(airline, DISTINCT ARRAY s.day FOR s IN schedule END) PARTITION BY HASH(meta().id) WHERE stops > 0 " +
+							`WITH {"defer_build":true,"num_replica":1}`
+						if got := mock.getCreateStatement("idx_by_airline"); got != want {
+							return fmt.Errorf("CREATE statement = %q, want %q", got, want)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+// TestAccQueryIndexResource_primary verifies creating a primary index.
+func TestAccQueryIndexResource_primary(t *testing.T) {
+	mockSrv, mock := newMockIndexServer(map[string]string{})
+	defer mockSrv.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testQueryIndexConfig(mockSrv.URL, "#primary", `
+  is_primary = true
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("capellaextras_query_index.test", "status", "Ready"),
+					func(_ *terraform.State) error {
+						want := "CREATE PRIMARY INDEX `#primary` ON `" + testBucket + "`.`_default`.`_default`"
+						if got := mock.getCreateStatement("#primary"); got != want {
+							return fmt.Errorf("CREATE statement = %q, want %q", got, want)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+// TestAccQueryIndexResource_updateRecreates verifies that a definition change drops and
+// recreates the index in place, while a name change replaces the resource.
+func TestAccQueryIndexResource_updateRecreates(t *testing.T) {
+	mockSrv, mock := newMockIndexServer(map[string]string{})
+	defer mockSrv.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testQueryIndexConfig(mockSrv.URL, "idx1", `
+  index_keys = ["a"]
+`),
+			},
+			{
+				Config: testQueryIndexConfig(mockSrv.URL, "idx1", `
+  index_keys = ["a", "b"]
+`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("capellaextras_query_index.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: func(_ *terraform.State) error {
+					if got := mock.getDropCallCount(); got != 1 {
+						return fmt.Errorf("drop calls = %d, want 1", got)
+					}
+					if got := mock.getCreateStatement("idx1"); !regexp.MustCompile(`\(a, b\)$`).MatchString(got) {
+						return fmt.Errorf("CREATE statement = %q, want keys (a, b)", got)
+					}
+					return nil
+				},
+			},
+			{
+				Config: testQueryIndexConfig(mockSrv.URL, "idx2", `
+  index_keys = ["a", "b"]
+`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("capellaextras_query_index.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+			},
+		},
+	})
+}
+
+// TestAccQueryIndexResource_deletedOutsideTerraform verifies that a dropped index is recreated.
+func TestAccQueryIndexResource_deletedOutsideTerraform(t *testing.T) {
+	mockSrv, mock := newMockIndexServer(map[string]string{})
+	defer mockSrv.Close()
+
+	config := testQueryIndexConfig(mockSrv.URL, "idx1", `
+  index_keys = ["a"]
+`)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				PreConfig: func() {
+					mock.mu.Lock()
+					delete(mock.indexStatuses, "idx1")
+					mock.mu.Unlock()
+				},
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("capellaextras_query_index.test", plancheck.ResourceActionCreate),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("capellaextras_query_index.test", "status", "Ready"),
+			},
+		},
+	})
+}
+
+// TestAccQueryIndexResource_definitionDrift verifies that a definition changed outside Terraform
+// is detected and the index recreated, while the query service's normalisation of the
+// configured expressions is not reported as drift.
+func TestAccQueryIndexResource_definitionDrift(t *testing.T) {
+	mockSrv, mock := newMockIndexServer(map[string]string{})
+	defer mockSrv.Close()
+
+	config := testQueryIndexConfig(mockSrv.URL, "idx1", `
+  index_keys = ["a", "lower(b)"]
+  where      = "type = 'airline'"
+`)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				PreConfig: func() {
+					mock.mu.Lock()
+					idx := mock.indexDefinitions["idx1"]
+					idx.SecondaryExprs = []string{"`a`", "lower((`b`))"}
+					idx.Where = "(`type` = \"airline\")"
+					mock.indexDefinitions["idx1"] = idx
+					mock.mu.Unlock()
+				},
+				Config:   config,
+				PlanOnly: true,
+			},
+			{
+				PreConfig: func() {
+					mock.mu.Lock()
+					idx := mock.indexDefinitions["idx1"]
+					idx.Where = "(`type` = \"hotel\")"
+					mock.indexDefinitions["idx1"] = idx
+					mock.mu.Unlock()
+				},
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("capellaextras_query_index.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: func(_ *terraform.State) error {
+					if got := mock.getDropCallCount(); got != 1 {
+						return fmt.Errorf("drop calls = %d, want 1", got)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+// TestAccQueryIndexResource_import verifies that an existing index is adopted by its ID and its
+// definition read back, and that a configuration spelling the same definition differently is
+// applied without recreating the index.
+func TestAccQueryIndexResource_import(t *testing.T) {
+	mockSrv, mock := newMockIndexServer(map[string]string{"idx_by_airline": "Created"})
+	defer mockSrv.Close()
+	mock.setIndexDefinition(indexes.Index{
+		IndexName:      "idx_by_airline",
+		SecondaryExprs: []string{"`airline`", "`stops`"},
+		Where:          "(`stops` > 0)",
+		NumReplica:     1,
+		DeferBuild:     true,
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testQueryIndexConfig(mockSrv.URL, "idx_by_airline", `
+  index_keys = ["airline", "stops"]
+  where      = "stops > 0"
+  with = {
+    defer_build = true
+    num_replica = 1
+  }
+`),
+				ResourceName:       "capellaextras_query_index.test",
+				ImportState:        true,
+				ImportStateId:      fmt.Sprintf("%s/%s/%s/%s/_default/_default/idx_by_airline", testOrgID, testProjID, testClusterID, testBucket),
+				ImportStatePersist: true,
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 {
+						return fmt.Errorf("imported %d resources, want 1", len(states))
+					}
+					attrs := states[0].Attributes
+					for key, want := range map[string]string{
+						"index_keys.#":     "2",
+						"index_keys.0":     "`airline`",
+						"where":            "(`stops` > 0)",
+						"is_primary":       "false",
+						"with.num_replica": "1",
+						"with.defer_build": "true",
+						"status":           "Created",
+					} {
+						if attrs[key] != want {
+							return fmt.Errorf("%s = %q, want %q", key, attrs[key], want)
+						}
+					}
+					return nil
+				},
+			},
+			{
+				Config: testQueryIndexConfig(mockSrv.URL, "idx_by_airline", `
+  index_keys = ["airline", "stops"]
+  where      = "stops > 0"
+  with = {
+    defer_build = true
+    num_replica = 1
+  }
+`),
+				Check: func(_ *terraform.State) error {
+					if got := mock.getDropCallCount(); got != 0 {
+						return fmt.Errorf("drop calls = %d, want 0", got)
+					}
+					if got := mock.getCreateStatement("idx_by_airline"); got != "" {
+						return fmt.Errorf("CREATE statement = %q, want none", got)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+// TestAccQueryIndexResource_invalidDefinition verifies the config validation of index_keys.
+func TestAccQueryIndexResource_invalidDefinition(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testQueryIndexConfig("http://127.0.0.1:0", "idx1", `
+  where = "a > 0"
+`),
+				ExpectError: regexp.MustCompile(`Missing Index Keys`),
+			},
+			{
+				Config: testQueryIndexConfig("http://127.0.0.1:0", "#primary", `
+  is_primary = true
+  index_keys = ["a"]
+`),
+				ExpectError: regexp.MustCompile(`Invalid Primary Index`),
+			},
+		},
+	})
+}
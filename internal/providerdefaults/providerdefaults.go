@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package providerdefaults resolves attributes that fall back to provider-level settings.
+package providerdefaults
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ResolveOrgProject falls back to the provider-level organization and project IDs when they are
+// not configured, reporting an error when neither supplies a value. kind names the configuring
+// block in error messages, for example "data source" or "action".
+func ResolveOrgProject(c *apiclient.Client, kind string, orgID, projectID types.String, diags *diag.Diagnostics) (types.String, types.String) {
+	if orgID.IsNull() {
+		orgID = types.StringValue(c.OrganizationID)
+	}
+	if projectID.IsNull() {
+		projectID = types.StringValue(c.ProjectID)
+	}
+	if orgID.ValueString() == "" {
+		diags.AddAttributeError(
+			path.Root("organization_id"),
+			"Missing Organization ID",
+			missingDetail("organization_id", kind),
+		)
+	}
+	if projectID.ValueString() == "" {
+		diags.AddAttributeError(
+			path.Root("project_id"),
+			"Missing Project ID",
+			missingDetail("project_id", kind),
+		)
+	}
+	return orgID, projectID
+}
+
+// SetProviderDefault plans attribute name of a resource as def when it is not set in the
+// configuration. It reports an error when neither the configuration nor the provider supplies a
+// value.
+func SetProviderDefault(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, name, label, def string) {
+	var configured types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(name), &configured)...)
+	if resp.Diagnostics.HasError() || !configured.IsNull() {
+		return
+	}
+
+	if def == "" {
+		resp.Diagnostics.AddAttributeError(path.Root(name), "Missing "+label, missingDetail(name, "resource"))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(name), types.StringValue(def))...)
+}
+
+// missingDetail describes how to supply attribute name, which is missing on a kind of block.
+func missingDetail(name, kind string) string {
+	return fmt.Sprintf("Set %[1]s on the %[2]s, or set a default %[1]s on the provider (or the CAPELLA_%[3]s environment variable).",
+		name, kind, strings.ToUpper(name))
+}
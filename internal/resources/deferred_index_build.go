@@ -6,17 +6,24 @@ package resources
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 
 	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
 	"github.com/cdsre/terraform-provider-capellaextras/api/indexes"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/indexwait"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/keyspace"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/providerdefaults"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
@@ -24,6 +31,9 @@ import (
 var _ resource.Resource = &DeferredIndexBuildResource{}
 var _ resource.ResourceWithConfigure = &DeferredIndexBuildResource{}
 var _ resource.ResourceWithModifyPlan = &DeferredIndexBuildResource{}
+var _ resource.ResourceWithValidateConfig = &DeferredIndexBuildResource{}
+var _ resource.ResourceWithImportState = &DeferredIndexBuildResource{}
+var _ resource.ResourceWithUpgradeState = &DeferredIndexBuildResource{}
 
 func NewDeferredIndexBuildResource() resource.Resource {
 	return &DeferredIndexBuildResource{}
@@ -44,8 +54,16 @@ type DeferredIndexBuildModel struct {
 	ScopeName            types.String `tfsdk:"scope_name"`
 	CollectionName       types.String `tfsdk:"collection_name"`
 	IndexNames           types.List   `tfsdk:"index_names"`
+	IndexNameRegex       types.String `tfsdk:"index_name_regex"`
+	DiscoverAll          types.Bool   `tfsdk:"discover_all"`
+	DiscoveredIndexNames types.List   `tfsdk:"discovered_index_names"`
 	BuildTriggerStatuses types.List   `tfsdk:"build_trigger_statuses"`
 	IndexStatuses        types.Map    `tfsdk:"index_statuses"`
+	Indexes              types.Map    `tfsdk:"indexes"`
+	WaitForReady         types.Bool   `tfsdk:"wait_for_ready"`
+	ReadyStatuses        types.List   `tfsdk:"ready_statuses"`
+	Timeout              types.String `tfsdk:"timeout"`
+	MaxIndexesPerBuild   types.Int64  `tfsdk:"max_indexes_per_build"`
 }
 
 func (r *DeferredIndexBuildResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -54,6 +72,7 @@ func (r *DeferredIndexBuildResource) Metadata(ctx context.Context, req resource.
 
 func (r *DeferredIndexBuildResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: deferredIndexBuildSchemaVersion,
 		MarkdownDescription: "Manages deferred index builds for Couchbase Capella query indexes. " +
 			"Tracks index build statuses and triggers builds only for indexes that have not yet been built. " +
 			"Unlike the `capellaextras_build_index` action, this resource participates in `terraform plan` " +
@@ -61,19 +80,22 @@ func (r *DeferredIndexBuildResource) Schema(ctx context.Context, req resource.Sc
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
-				Computed:            true,
-				MarkdownDescription: "Composite identifier: `{organization_id}/{project_id}/{cluster_id}/{bucket_name}`.",
+				Computed: true,
+				MarkdownDescription: "Composite identifier: `{organization_id}/{project_id}/{cluster_id}/{bucket_name}/{scope_name}/{collection_name}`, " +
+					"using `_default` for an unset scope or collection.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
 			"organization_id": schema.StringAttribute{
-				MarkdownDescription: "The organization ID where the indexes are located.",
-				Required:            true,
+				MarkdownDescription: "The organization ID where the indexes are located. Defaults to the provider `organization_id`.",
+				Optional:            true,
+				Computed:            true,
 			},
 			"project_id": schema.StringAttribute{
-				MarkdownDescription: "The project ID where the indexes are located.",
-				Required:            true,
+				MarkdownDescription: "The project ID where the indexes are located. Defaults to the provider `project_id`.",
+				Optional:            true,
+				Computed:            true,
 			},
 			"cluster_id": schema.StringAttribute{
 				MarkdownDescription: "The cluster ID where the indexes are located.",
@@ -92,9 +114,26 @@ func (r *DeferredIndexBuildResource) Schema(ctx context.Context, req resource.Sc
 				Optional:            true,
 			},
 			"index_names": schema.ListAttribute{
-				ElementType:         types.StringType,
-				MarkdownDescription: "The names of the deferred indexes to manage builds for.",
-				Required:            true,
+				ElementType: types.StringType,
+				MarkdownDescription: "The names of the deferred indexes to manage builds for. " +
+					"Exactly one of `index_names`, `index_name_regex` or `discover_all` must be set.",
+				Optional: true,
+			},
+			"index_name_regex": schema.StringAttribute{
+				MarkdownDescription: "Manage builds for every index in the keyspace whose name matches this " +
+					"[RE2](https://github.com/google/re2/wiki/Syntax) regular expression, instead of listing `index_names`. " +
+					"The expression is not anchored; use `^` and `$` to match whole names.",
+				Optional: true,
+			},
+			"discover_all": schema.BoolAttribute{
+				MarkdownDescription: "Manage builds for every index in the keyspace, instead of listing `index_names`.",
+				Optional:            true,
+			},
+			"discovered_index_names": schema.ListAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "The indexes found in the keyspace by `index_name_regex` or `discover_all`, sorted by name. " +
+					"Refreshed on `terraform plan`. Null when `index_names` is set.",
+				Computed: true,
 			},
 			"build_trigger_statuses": schema.ListAttribute{
 				ElementType: types.StringType,
@@ -114,10 +153,99 @@ func (r *DeferredIndexBuildResource) Schema(ctx context.Context, req resource.Sc
 					"Updated after each apply and refreshed on `terraform plan`.",
 				Computed: true,
 			},
+			"indexes": observedIndexesAttribute("index name"),
+			"wait_for_ready": schema.BoolAttribute{
+				MarkdownDescription: "Wait for every triggered index to reach one of `ready_statuses` before " +
+					"completing the apply. Defaults to `false`, in which case builds run in the background.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"ready_statuses": schema.ListAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "Index statuses that count as a finished build when `wait_for_ready` is `true`. " +
+					"Defaults to `[\"Ready\"]`. An index entering the `Error` status fails the apply.",
+				Optional: true,
+				Computed: true,
+				Default: listdefault.StaticValue(types.ListValueMust(
+					types.StringType,
+					[]attr.Value{types.StringValue("Ready")},
+				)),
+			},
+			"timeout": schema.StringAttribute{
+				MarkdownDescription: "How long to wait for triggered builds when `wait_for_ready` is `true`, " +
+					"as a Go duration string (e.g. `30m`, `1h`). Defaults to `" + indexwait.DefaultTimeout + "`.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(indexwait.DefaultTimeout),
+			},
+			"max_indexes_per_build": schema.Int64Attribute{
+				MarkdownDescription: "Split the indexes to build into `BUILD INDEX` statements of at most this many indexes, " +
+					"submitted one after another. A failed statement does not stop the remaining ones. " +
+					"By default all indexes are built in a single statement.",
+				Optional: true,
+			},
 		},
 	}
 }
 
+func (r *DeferredIndexBuildResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var timeout types.String
+	var maxPerBuild types.Int64
+	var indexNames types.List
+	var indexNameRegex types.String
+	var discoverAll types.Bool
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("timeout"), &timeout)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("max_indexes_per_build"), &maxPerBuild)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("index_names"), &indexNames)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("index_name_regex"), &indexNameRegex)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("discover_all"), &discoverAll)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Unknown values count as set, so a mode coming from another resource is not reported missing.
+	modes := 0
+	if !indexNames.IsNull() {
+		modes++
+	}
+	if !indexNameRegex.IsNull() {
+		modes++
+	}
+	if discoverAll.IsUnknown() || discoverAll.ValueBool() {
+		modes++
+	}
+	if modes != 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("index_names"),
+			"Invalid Index Selection",
+			"Exactly one of index_names, index_name_regex or discover_all = true must be set.",
+		)
+	}
+
+	if !indexNameRegex.IsNull() && !indexNameRegex.IsUnknown() {
+		if _, err := regexp.Compile(indexNameRegex.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("index_name_regex"),
+				"Invalid Index Name Regex",
+				fmt.Sprintf("Cannot parse %q as a regular expression: %v", indexNameRegex.ValueString(), err),
+			)
+		}
+	}
+
+	if !timeout.IsNull() && !timeout.IsUnknown() {
+		indexwait.ParseTimeout(timeout, &resp.Diagnostics)
+	}
+
+	if !maxPerBuild.IsNull() && !maxPerBuild.IsUnknown() && maxPerBuild.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_indexes_per_build"),
+			"Invalid Max Indexes Per Build",
+			fmt.Sprintf("max_indexes_per_build must be at least 1, got %d.", maxPerBuild.ValueInt64()),
+		)
+	}
+}
+
 func (r *DeferredIndexBuildResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -135,7 +263,9 @@ func (r *DeferredIndexBuildResource) Configure(ctx context.Context, req resource
 	r.client = client
 }
 
-// ModifyPlan marks index_statuses as unknown — forcing an Update — in two situations:
+// ModifyPlan fills organization_id and project_id from the provider defaults when they are not
+// configured, then marks index_statuses and indexes (and discovered_index_names, when indexes are
+// discovered) as unknown — forcing an Update — in two situations:
 //
 //  1. A stored status matches build_trigger_statuses (e.g. "Created" after an index
 //     was recreated externally).
@@ -147,8 +277,36 @@ func (r *DeferredIndexBuildResource) Configure(ctx context.Context, req resource
 //     the same apply (ahead of this resource due to the dependency chain) — the build
 //     is triggered in a single apply rather than requiring a second run.
 func (r *DeferredIndexBuildResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
-	// Skip on create (no prior state) and destroy (no plan).
-	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+	// Skip on destroy (no plan).
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	if r.client != nil {
+		providerdefaults.SetProviderDefault(ctx, req, resp, "organization_id", "Organization ID", r.client.OrganizationID)
+		providerdefaults.SetProviderDefault(ctx, req, resp, "project_id", "Project ID", r.client.ProjectID)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Keep id in step with the keyspace it identifies, so that changing any part of
+	// the keyspace plans the new id rather than reusing the one from state.
+	var planned DeferredIndexBuildModel
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &planned)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if keyspaceKnown(&planned) {
+		resp.Diagnostics.Append(
+			resp.Plan.SetAttribute(ctx, path.Root("id"), types.StringValue(deferredIndexBuildID(&planned)))...,
+		)
+	} else {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), types.StringUnknown())...)
+	}
+
+	// Skip on create (no prior state).
+	if req.State.Raw.IsNull() {
 		return
 	}
 
@@ -188,9 +346,7 @@ func (r *DeferredIndexBuildResource) ModifyPlan(ctx context.Context, req resourc
 	// Check 1: any stored status is a build trigger.
 	for _, status := range statusMap {
 		if triggerSet[status] {
-			resp.Diagnostics.Append(
-				resp.Plan.SetAttribute(ctx, path.Root("index_statuses"), types.MapUnknown(types.StringType))...,
-			)
+			planBuild(ctx, &plan, resp)
 			return
 		}
 	}
@@ -210,14 +366,28 @@ func (r *DeferredIndexBuildResource) ModifyPlan(ctx context.Context, req resourc
 	}
 	for _, name := range planIndexNames {
 		if _, exists := statusMap[name]; !exists {
-			resp.Diagnostics.Append(
-				resp.Plan.SetAttribute(ctx, path.Root("index_statuses"), types.MapUnknown(types.StringType))...,
-			)
+			planBuild(ctx, &plan, resp)
 			return
 		}
 	}
 }
 
+// planBuild marks index_statuses and indexes as unknown so that Update runs. Discovered indexes
+// are marked unknown too, as indexes created during the apply are discovered by Update.
+func planBuild(ctx context.Context, plan *DeferredIndexBuildModel, resp *resource.ModifyPlanResponse) {
+	resp.Diagnostics.Append(
+		resp.Plan.SetAttribute(ctx, path.Root("index_statuses"), types.MapUnknown(types.StringType))...,
+	)
+	resp.Diagnostics.Append(
+		resp.Plan.SetAttribute(ctx, path.Root("indexes"), types.MapUnknown(observedIndexType))...,
+	)
+	if discoveryEnabled(plan) {
+		resp.Diagnostics.Append(
+			resp.Plan.SetAttribute(ctx, path.Root("discovered_index_names"), types.ListUnknown(types.StringType))...,
+		)
+	}
+}
+
 func (r *DeferredIndexBuildResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data DeferredIndexBuildModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
@@ -225,8 +395,9 @@ func (r *DeferredIndexBuildResource) Create(ctx context.Context, req resource.Cr
 		return
 	}
 
-	r.performBuild(ctx, &data, &resp.Diagnostics)
-	if resp.Diagnostics.HasError() {
+	// Save partial progress: once builds were submitted, index_statuses records the ones that
+	// started even if others failed.
+	if submitted := r.performBuild(ctx, &data, types.MapNull(observedIndexType), &resp.Diagnostics); resp.Diagnostics.HasError() && !submitted {
 		return
 	}
 
@@ -240,47 +411,27 @@ func (r *DeferredIndexBuildResource) Read(ctx context.Context, req resource.Read
 		return
 	}
 
-	scope, collection := resolveDefaults(&data)
-
-	var indexNames []string
-	resp.Diagnostics.Append(data.IndexNames.ElementsAs(ctx, &indexNames, false)...)
+	indexNames := r.managedIndexNames(ctx, &data, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	statusMap := make(map[string]attr.Value, len(indexNames))
-	for _, indexName := range indexNames {
-		res, err := indexes.GetIndexBuildStatus(ctx, r.client, &indexes.IndexBuildStatusRequest{
-			OrganizationId: data.OrganizationId.ValueString(),
-			ProjectId:      data.ProjectId.ValueString(),
-			ClusterId:      data.ClusterId.ValueString(),
-			Bucket:         data.BucketName.ValueString(),
-			IndexName:      indexName,
-			Scope:          scope,
-			Collection:     collection,
-		})
-		if err != nil {
-			if isNotFoundError(err) {
-				// Index does not exist yet (e.g. deleted outside Terraform and not yet recreated).
-				// Omit it from index_statuses so the plan can proceed; once the Capella provider
-				// recreates it, the next Read will pick it up and ModifyPlan will trigger a build.
-				continue
-			}
-			resp.Diagnostics.AddError(
-				"Get Index Build Status Failed",
-				fmt.Sprintf("Cannot get build status for index %q: %v", indexName, err),
-			)
-			return
-		}
-		statusMap[indexName] = types.StringValue(res.Status)
+	// Indexes that do not exist yet (e.g. deleted outside Terraform and not yet recreated)
+	// are omitted from index_statuses so the plan can proceed; once the Capella provider
+	// recreates them, the next Read will pick them up and ModifyPlan will trigger a build.
+	builds := r.keyspaceBuilds(&data, indexNames)
+	builds.observe(ctx, nil, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	indexStatuses, diags := types.MapValue(types.StringType, statusMap)
+	indexStatuses, indexDetails, diags := observedIndexValues(builds.observed, data.Indexes)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 	data.IndexStatuses = indexStatuses
+	data.Indexes = indexDetails
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -292,11 +443,84 @@ func (r *DeferredIndexBuildResource) Update(ctx context.Context, req resource.Up
 		return
 	}
 
-	r.performBuild(ctx, &data, &resp.Diagnostics)
+	var prior types.Map
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("indexes"), &prior)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	// Save partial progress: once builds were submitted, index_statuses records the ones that
+	// started even if others failed.
+	if submitted := r.performBuild(ctx, &data, prior, &resp.Diagnostics); resp.Diagnostics.HasError() && !submitted {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// ImportState adopts existing indexes from an ID of the form
+// `{organization_id}/{project_id}/{cluster_id}/{bucket_name}/{scope_name}/{collection_name}/{index_names}`,
+// where index_names is comma separated. Read then populates index_statuses from the API.
+func (r *DeferredIndexBuildResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts := strings.Split(req.ID, "/")
+	if len(parts) != 7 {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form "+
+				"organization_id/project_id/cluster_id/bucket_name/scope_name/collection_name/index1,index2, got: %q", req.ID),
+		)
+		return
+	}
+	for i, part := range parts {
+		if part == "" {
+			resp.Diagnostics.AddError(
+				"Unexpected Import Identifier",
+				fmt.Sprintf("Import identifier %q has an empty component at position %d.", req.ID, i+1),
+			)
+			return
+		}
+	}
+
+	var indexNames []attr.Value
+	for _, name := range strings.Split(parts[6], ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			resp.Diagnostics.AddError(
+				"Unexpected Import Identifier",
+				fmt.Sprintf("Import identifier %q contains an empty index name.", req.ID),
+			)
+			return
+		}
+		indexNames = append(indexNames, types.StringValue(name))
+	}
+
+	data := DeferredIndexBuildModel{
+		OrganizationId: types.StringValue(parts[0]),
+		ProjectId:      types.StringValue(parts[1]),
+		ClusterId:      types.StringValue(parts[2]),
+		BucketName:     types.StringValue(parts[3]),
+		// The default scope and collection are stored as null so that configurations
+		// which omit scope_name/collection_name import without a diff.
+		ScopeName:            optionalKeyspaceName(parts[4]),
+		CollectionName:       optionalKeyspaceName(parts[5]),
+		IndexNames:           types.ListValueMust(types.StringType, indexNames),
+		IndexNameRegex:       types.StringNull(),
+		DiscoverAll:          types.BoolNull(),
+		DiscoveredIndexNames: types.ListNull(types.StringType),
+		BuildTriggerStatuses: types.ListValueMust(types.StringType, []attr.Value{
+			types.StringValue("Created"),
+		}),
+		IndexStatuses: types.MapNull(types.StringType),
+		Indexes:       types.MapNull(observedIndexType),
+		WaitForReady:  types.BoolValue(false),
+		ReadyStatuses: types.ListValueMust(types.StringType, []attr.Value{
+			types.StringValue("Ready"),
+		}),
+		Timeout:            types.StringValue(indexwait.DefaultTimeout),
+		MaxIndexesPerBuild: types.Int64Null(),
+	}
+	data.Id = types.StringValue(deferredIndexBuildID(&data))
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -305,114 +529,161 @@ func (r *DeferredIndexBuildResource) Delete(ctx context.Context, req resource.De
 	// There is nothing to delete
 }
 
-// performBuild fetches current index statuses, triggers a build for any indexes whose status
-// matches build_trigger_statuses, and stores the resulting statuses in data.IndexStatuses.
-// Triggered indexes are recorded as "Building" in state without an extra API call.
-func (r *DeferredIndexBuildResource) performBuild(ctx context.Context, data *DeferredIndexBuildModel, diagnostics *diag.Diagnostics) {
-	scope, collection := resolveDefaults(data)
-
-	var indexNames []string
-	diagnostics.Append(data.IndexNames.ElementsAs(ctx, &indexNames, false)...)
+// performBuild fetches current index statuses of the managed indexes, triggers a build for any
+// indexes whose status matches build_trigger_statuses, and stores the resulting statuses in
+// data.IndexStatuses and data.Indexes, as described on keyspaceBuilds.run. Indexes whose details
+// match prior, the indexes attribute from state, keep their last_checked.
+//
+// It reports whether builds were submitted. If so, data.IndexStatuses reflects the builds that
+// started even when others failed, and the caller should save it alongside the errors.
+func (r *DeferredIndexBuildResource) performBuild(ctx context.Context, data *DeferredIndexBuildModel, prior types.Map, diagnostics *diag.Diagnostics) (submitted bool) {
+	indexNames := r.managedIndexNames(ctx, data, diagnostics)
 	if diagnostics.HasError() {
-		return
+		return false
 	}
 
-	var triggerStatuses []string
-	diagnostics.Append(data.BuildTriggerStatuses.ElementsAs(ctx, &triggerStatuses, false)...)
-	if diagnostics.HasError() {
-		return
+	// Indexes that do not exist yet are skipped so the others can still be built. They
+	// will appear in index_statuses once the Capella provider recreates them.
+	builds := r.keyspaceBuilds(data, indexNames)
+	submitted = builds.run(ctx, buildSettings{
+		BuildTriggerStatuses: data.BuildTriggerStatuses,
+		WaitForReady:         data.WaitForReady,
+		ReadyStatuses:        data.ReadyStatuses,
+		Timeout:              data.Timeout,
+		MaxIndexesPerBuild:   data.MaxIndexesPerBuild,
+	}, diagnostics)
+	if diagnostics.HasError() && !submitted {
+		return false
 	}
 
-	triggerSet := make(map[string]bool, len(triggerStatuses))
-	for _, s := range triggerStatuses {
-		triggerSet[s] = true
+	indexStatuses, indexDetails, diags := observedIndexValues(builds.observed, prior)
+	diagnostics.Append(diags...)
+	if diags.HasError() {
+		return false
 	}
+	data.IndexStatuses = indexStatuses
+	data.Indexes = indexDetails
+	data.Id = types.StringValue(deferredIndexBuildID(data))
+	return submitted
+}
 
-	statusMap := make(map[string]attr.Value, len(indexNames))
-	var toBuild []string
-
-	for _, indexName := range indexNames {
-		res, err := indexes.GetIndexBuildStatus(ctx, r.client, &indexes.IndexBuildStatusRequest{
-			OrganizationId: data.OrganizationId.ValueString(),
-			ProjectId:      data.ProjectId.ValueString(),
-			ClusterId:      data.ClusterId.ValueString(),
-			Bucket:         data.BucketName.ValueString(),
-			IndexName:      indexName,
-			Scope:          scope,
-			Collection:     collection,
-		})
-		if err != nil {
-			if isNotFoundError(err) {
-				// Index does not exist yet; skip it so other indexes can still be built.
-				// It will appear in index_statuses once the Capella provider recreates it.
-				continue
-			}
-			diagnostics.AddError(
-				"Get Index Build Status Failed",
-				fmt.Sprintf("Cannot get build status for index %q: %v", indexName, err),
-			)
-			return
-		}
-		statusMap[indexName] = types.StringValue(res.Status)
-		if triggerSet[res.Status] {
-			toBuild = append(toBuild, indexName)
-		}
+// keyspaceBuilds returns the builds of indexNames in the keyspace of data.
+func (r *DeferredIndexBuildResource) keyspaceBuilds(data *DeferredIndexBuildModel, indexNames []string) *keyspaceBuilds {
+	ks := buildKeyspace{bucket: data.BucketName.ValueString(), indexNames: indexNames}
+	ks.scope, ks.collection = resolveDefaults(data)
+	return newKeyspaceBuilds(r.client, data.OrganizationId, data.ProjectId, data.ClusterId, []buildKeyspace{ks}, false)
+}
+
+// managedIndexNames returns the indexes the resource manages: index_names, or the indexes of the
+// keyspace selected by index_name_regex or discover_all, which are also stored in
+// data.DiscoveredIndexNames.
+func (r *DeferredIndexBuildResource) managedIndexNames(ctx context.Context, data *DeferredIndexBuildModel, diagnostics *diag.Diagnostics) []string {
+	if !discoveryEnabled(data) {
+		var indexNames []string
+		diagnostics.Append(data.IndexNames.ElementsAs(ctx, &indexNames, false)...)
+		data.DiscoveredIndexNames = types.ListNull(types.StringType)
+		return indexNames
 	}
 
-	if len(toBuild) > 0 {
-		_, err := indexes.BuildDeferredIndexes(ctx, r.client, &indexes.IndexBuildRequest{
-			OrganizationId: data.OrganizationId.ValueString(),
-			ProjectId:      data.ProjectId.ValueString(),
-			ClusterId:      data.ClusterId.ValueString(),
-			Bucket:         data.BucketName.ValueString(),
-			Collection:     collection,
-			Scope:          scope,
-			IndexNames:     toBuild,
-		})
-		if err != nil {
-			diagnostics.AddError(
-				"Build Deferred Indexes Failed",
-				fmt.Sprintf("Cannot build deferred indexes: %v", err),
+	var pattern *regexp.Regexp
+	if !data.IndexNameRegex.IsNull() {
+		var err error
+		if pattern, err = regexp.Compile(data.IndexNameRegex.ValueString()); err != nil {
+			diagnostics.AddAttributeError(
+				path.Root("index_name_regex"),
+				"Invalid Index Name Regex",
+				fmt.Sprintf("Cannot parse %q as a regular expression: %v", data.IndexNameRegex.ValueString(), err),
 			)
-			return
+			return nil
 		}
+	}
 
-		// Reflect the triggered build in state without an extra API call.
-		// Read() will correct this to the real status on the next plan refresh.
-		for _, idx := range toBuild {
-			statusMap[idx] = types.StringValue("Building")
+	scope, collection := resolveDefaults(data)
+	res, err := indexes.ListIndexes(ctx, r.client, &indexes.ListIndexesRequest{
+		OrganizationId: data.OrganizationId.ValueString(),
+		ProjectId:      data.ProjectId.ValueString(),
+		ClusterId:      data.ClusterId.ValueString(),
+		Bucket:         data.BucketName.ValueString(),
+		Scope:          scope,
+		Collection:     collection,
+	})
+	if err != nil {
+		diagnostics.AddError(
+			"List Indexes Failed",
+			fmt.Sprintf("Cannot list indexes to discover in keyspace %s.%s.%s: %v", data.BucketName.ValueString(), scope, collection, err),
+		)
+		return nil
+	}
+
+	indexNames := []string{}
+	for _, def := range res.Definitions {
+		if pattern == nil || pattern.MatchString(def.IndexName) {
+			indexNames = append(indexNames, def.IndexName)
 		}
 	}
+	sort.Strings(indexNames)
 
-	indexStatuses, diags := types.MapValue(types.StringType, statusMap)
+	discovered, diags := types.ListValueFrom(ctx, types.StringType, indexNames)
 	diagnostics.Append(diags...)
-	if diagnostics.HasError() {
-		return
+	data.DiscoveredIndexNames = discovered
+	return indexNames
+}
+
+// discoveryEnabled reports whether the managed indexes are discovered from the keyspace rather
+// than listed in index_names.
+func discoveryEnabled(data *DeferredIndexBuildModel) bool {
+	return data.DiscoverAll.ValueBool() || !data.IndexNameRegex.IsNull()
+}
+
+// addBuildFailureDiagnostics reports every failed BUILD INDEX batch in res, naming the indexes
+// it covered and the indexes whose builds were started by the other batches.
+func addBuildFailureDiagnostics(res *indexes.IndexBuildResult, diagnostics *diag.Diagnostics) {
+	started := "No index builds were started."
+	if len(res.Built) > 0 {
+		started = fmt.Sprintf("Builds were started for: %s.", strings.Join(res.Built, ", "))
 	}
-	data.IndexStatuses = indexStatuses
-	data.Id = types.StringValue(fmt.Sprintf("%s/%s/%s/%s",
+	for _, failed := range res.Failed {
+		diagnostics.AddError(
+			"Build Deferred Indexes Failed",
+			fmt.Sprintf("Cannot build indexes %s: %v\n\n%s", strings.Join(failed.IndexNames, ", "), failed.Err, started),
+		)
+	}
+}
+
+// deferredIndexBuildID returns the composite resource ID for the keyspace in data.
+func deferredIndexBuildID(data *DeferredIndexBuildModel) string {
+	scope, collection := resolveDefaults(data)
+	return strings.Join([]string{
 		data.OrganizationId.ValueString(),
 		data.ProjectId.ValueString(),
 		data.ClusterId.ValueString(),
 		data.BucketName.ValueString(),
-	))
+		scope,
+		collection,
+	}, "/")
 }
 
-// isNotFoundError reports whether an API error is an HTTP 404 (index does not exist yet).
-func isNotFoundError(err error) bool {
-	return strings.Contains(err.Error(), "status 404")
+// keyspaceKnown reports whether every attribute that makes up the resource ID is known.
+func keyspaceKnown(data *DeferredIndexBuildModel) bool {
+	for _, v := range []types.String{
+		data.OrganizationId, data.ProjectId, data.ClusterId, data.BucketName, data.ScopeName, data.CollectionName,
+	} {
+		if v.IsUnknown() {
+			return false
+		}
+	}
+	return true
 }
 
-func resolveDefaults(data *DeferredIndexBuildModel) (scope, collection string) {
-	if data.ScopeName.IsNull() || data.ScopeName.IsUnknown() {
-		scope = "_default"
-	} else {
-		scope = data.ScopeName.ValueString()
+// optionalKeyspaceName returns null for the default scope or collection name.
+func optionalKeyspaceName(name string) types.String {
+	if name == keyspace.DefaultName {
+		return types.StringNull()
 	}
-	if data.CollectionName.IsNull() || data.CollectionName.IsUnknown() {
-		collection = "_default"
-	} else {
-		collection = data.CollectionName.ValueString()
-	}
-	return
+	return types.StringValue(name)
+}
+
+// resolveDefaults returns the scope and collection names of the keyspace in data.
+func resolveDefaults(data *DeferredIndexBuildModel) (scope, collection string) {
+	return keyspace.NameOrDefault(data.ScopeName), keyspace.NameOrDefault(data.CollectionName)
 }
@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package resources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cdsre/terraform-provider-capellaextras/internal/indexwait"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// deferredIndexBuildSchemaVersion is the current version of the deferred_index_build schema.
+//
+// To change the schema in a way existing state cannot be read as-is (renaming or removing an
+// attribute, changing a type or the id format), bump this version and append a step to
+// deferredIndexBuildUpgradeSteps that migrates state from the previous version. Adding a new
+// optional attribute does not need a new version: it reads as null from older state.
+const deferredIndexBuildSchemaVersion = 1
+
+// deferredIndexBuildUpgradeSteps[n] migrates state from version n to version n+1.
+var deferredIndexBuildUpgradeSteps = []stateUpgradeStep{
+	upgradeDeferredIndexBuildV0ToV1,
+}
+
+// stateUpgradeStep migrates the raw JSON attributes of one schema version to the next, in place.
+type stateUpgradeStep func(state map[string]any) error
+
+// UpgradeState migrates state written by any earlier schema version to the current version by
+// running every upgrade step from the prior version onwards.
+func (r *DeferredIndexBuildResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	upgraders := make(map[int64]resource.StateUpgrader, len(deferredIndexBuildUpgradeSteps))
+	for version := range deferredIndexBuildUpgradeSteps {
+		upgraders[int64(version)] = resource.StateUpgrader{
+			StateUpgrader: chainStateUpgradeSteps(deferredIndexBuildUpgradeSteps[version:]),
+		}
+	}
+	return upgraders
+}
+
+// chainStateUpgradeSteps returns a StateUpgrader that applies steps in order to the raw prior state.
+// Working on raw JSON avoids redeclaring every historical schema; the result is validated
+// against the current schema by the framework.
+func chainStateUpgradeSteps(steps []stateUpgradeStep) func(context.Context, resource.UpgradeStateRequest, *resource.UpgradeStateResponse) {
+	return func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+		if req.RawState == nil || req.RawState.JSON == nil {
+			resp.Diagnostics.AddError(
+				"Unable to Upgrade Resource State",
+				"This resource can only upgrade state written by Terraform 0.12 or later. "+
+					"Please report this issue to the provider developers.",
+			)
+			return
+		}
+
+		var state map[string]any
+		dec := json.NewDecoder(bytes.NewReader(req.RawState.JSON))
+		dec.UseNumber()
+		if err := dec.Decode(&state); err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Upgrade Resource State",
+				fmt.Sprintf("Cannot decode prior state: %v", err),
+			)
+			return
+		}
+
+		for _, step := range steps {
+			if err := step(state); err != nil {
+				resp.Diagnostics.AddError(
+					"Unable to Upgrade Resource State",
+					fmt.Sprintf("Cannot upgrade prior state: %v", err),
+				)
+				return
+			}
+		}
+
+		upgraded, err := json.Marshal(state)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Upgrade Resource State",
+				fmt.Sprintf("Cannot encode upgraded state: %v", err),
+			)
+			return
+		}
+		resp.DynamicValue = &tfprotov6.DynamicValue{JSON: upgraded}
+	}
+}
+
+// upgradeDeferredIndexBuildV0ToV1 rewrites id from `{org}/{project}/{cluster}/{bucket}` to include
+// the scope and collection. Attributes added during version 0 may be absent from older state,
+// so their defaults are filled in too.
+func upgradeDeferredIndexBuildV0ToV1(state map[string]any) error {
+	setStateDefault(state, "wait_for_ready", false)
+	setStateDefault(state, "ready_statuses", []any{"Ready"})
+	setStateDefault(state, "timeout", indexwait.DefaultTimeout)
+
+	scope := stateString(state, "scope_name")
+	if scope == "" {
+		scope = "_default"
+	}
+	collection := stateString(state, "collection_name")
+	if collection == "" {
+		collection = "_default"
+	}
+	state["id"] = strings.Join([]string{
+		stateString(state, "organization_id"),
+		stateString(state, "project_id"),
+		stateString(state, "cluster_id"),
+		stateString(state, "bucket_name"),
+		scope,
+		collection,
+	}, "/")
+	return nil
+}
+
+// setStateDefault sets name to v when it is absent or null in state.
+func setStateDefault(state map[string]any, name string, v any) {
+	if state[name] == nil {
+		state[name] = v
+	}
+}
+
+// stateString returns the string value of name in state, or "" when it is absent or null.
+func stateString(state map[string]any, name string) string {
+	s, _ := state[name].(string)
+	return s
+}
@@ -0,0 +1,128 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cdsre/terraform-provider-capellaextras/internal/indexwait"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// upgradeDeferredIndexBuildState runs the resource's upgrader for version against rawJSON and
+// decodes the result with the current schema.
+func upgradeDeferredIndexBuildState(t *testing.T, version int64, rawJSON string) DeferredIndexBuildModel {
+	t.Helper()
+	ctx := context.Background()
+	r := &DeferredIndexBuildResource{}
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	upgrader, ok := r.UpgradeState(ctx)[version]
+	if !ok {
+		t.Fatalf("no state upgrader for version %d", version)
+	}
+
+	var resp resource.UpgradeStateResponse
+	upgrader.StateUpgrader(ctx, resource.UpgradeStateRequest{
+		RawState: &tfprotov6.RawState{JSON: []byte(rawJSON)},
+	}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("StateUpgrader() diagnostics: %v", resp.Diagnostics)
+	}
+
+	raw, err := resp.DynamicValue.Unmarshal(schemaResp.Schema.Type().TerraformType(ctx))
+	if err != nil {
+		t.Fatalf("upgraded state does not match the current schema: %v", err)
+	}
+	var data DeferredIndexBuildModel
+	diags := tfsdk.State{Raw: raw, Schema: schemaResp.Schema}.Get(ctx, &data)
+	if diags.HasError() {
+		t.Fatalf("State.Get() diagnostics: %v", diags)
+	}
+	return data
+}
+
+// Every version below the current one needs an upgrader, and every step must lead to the
+// current version.
+func TestDeferredIndexBuildUpgradeState_coversEveryVersion(t *testing.T) {
+	ctx := context.Background()
+	r := &DeferredIndexBuildResource{}
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	if got := int64(len(deferredIndexBuildUpgradeSteps)); got != schemaResp.Schema.Version {
+		t.Fatalf("%d upgrade steps for schema version %d", got, schemaResp.Schema.Version)
+	}
+	upgraders := r.UpgradeState(ctx)
+	for v := int64(0); v < schemaResp.Schema.Version; v++ {
+		if _, ok := upgraders[v]; !ok {
+			t.Errorf("no state upgrader for version %d", v)
+		}
+	}
+}
+
+// Version 0 state written before the wait attributes existed.
+func TestDeferredIndexBuildUpgradeState_v0(t *testing.T) {
+	data := upgradeDeferredIndexBuildState(t, 0, `{
+		"id": "org/proj/cluster/bucket",
+		"organization_id": "org",
+		"project_id": "proj",
+		"cluster_id": "cluster",
+		"bucket_name": "bucket",
+		"scope_name": null,
+		"collection_name": null,
+		"index_names": ["idx1", "idx2"],
+		"build_trigger_statuses": ["Created"],
+		"index_statuses": {"idx1": "Ready", "idx2": "Building"}
+	}`)
+
+	if got, want := data.Id.ValueString(), "org/proj/cluster/bucket/_default/_default"; got != want {
+		t.Errorf("id = %q, want %q", got, want)
+	}
+	if data.WaitForReady.IsNull() || data.WaitForReady.ValueBool() {
+		t.Errorf("wait_for_ready = %v, want false", data.WaitForReady)
+	}
+	if got := data.Timeout.ValueString(); got != indexwait.DefaultTimeout {
+		t.Errorf("timeout = %q, want %q", got, indexwait.DefaultTimeout)
+	}
+	if got := len(data.ReadyStatuses.Elements()); got != 1 {
+		t.Errorf("ready_statuses has %d elements, want 1", got)
+	}
+	if got := len(data.IndexStatuses.Elements()); got != 2 {
+		t.Errorf("index_statuses has %d elements, want 2", got)
+	}
+}
+
+// Version 0 state that already has a custom keyspace and wait settings keeps them.
+func TestDeferredIndexBuildUpgradeState_v0CustomKeyspace(t *testing.T) {
+	data := upgradeDeferredIndexBuildState(t, 0, `{
+		"id": "org/proj/cluster/bucket",
+		"organization_id": "org",
+		"project_id": "proj",
+		"cluster_id": "cluster",
+		"bucket_name": "bucket",
+		"scope_name": "inventory",
+		"collection_name": "airline",
+		"index_names": ["idx1"],
+		"build_trigger_statuses": ["Created", "Error"],
+		"index_statuses": {"idx1": "Ready"},
+		"wait_for_ready": true,
+		"ready_statuses": ["Ready", "Online"],
+		"timeout": "1h"
+	}`)
+
+	if got, want := data.Id.ValueString(), "org/proj/cluster/bucket/inventory/airline"; got != want {
+		t.Errorf("id = %q, want %q", got, want)
+	}
+	if !data.WaitForReady.ValueBool() || data.Timeout.ValueString() != "1h" || len(data.ReadyStatuses.Elements()) != 2 {
+		t.Errorf("wait settings not preserved: wait_for_ready=%v timeout=%v ready_statuses=%v",
+			data.WaitForReady, data.Timeout, data.ReadyStatuses)
+	}
+}
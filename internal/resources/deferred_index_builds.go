@@ -0,0 +1,527 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package resources
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/indexwait"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/keyspace"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/providerdefaults"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.Resource = &DeferredIndexBuildsResource{}
+var _ resource.ResourceWithConfigure = &DeferredIndexBuildsResource{}
+var _ resource.ResourceWithModifyPlan = &DeferredIndexBuildsResource{}
+var _ resource.ResourceWithValidateConfig = &DeferredIndexBuildsResource{}
+
+// deferredIndexBuildsIDMarker separates the cluster from the keyspace digest in the resource ID.
+// It gives the ID five components, a count no other ID of this provider has.
+const deferredIndexBuildsIDMarker = "keyspaces"
+
+func NewDeferredIndexBuildsResource() resource.Resource {
+	return &DeferredIndexBuildsResource{}
+}
+
+// DeferredIndexBuildsResource manages deferred index builds across several keyspaces of a cluster.
+type DeferredIndexBuildsResource struct {
+	client *apiclient.Client
+}
+
+// DeferredIndexBuildsModel describes the resource data model.
+type DeferredIndexBuildsModel struct {
+	Id                   types.String `tfsdk:"id"`
+	OrganizationId       types.String `tfsdk:"organization_id"`
+	ProjectId            types.String `tfsdk:"project_id"`
+	ClusterId            types.String `tfsdk:"cluster_id"`
+	Keyspaces            types.List   `tfsdk:"keyspaces"`
+	BuildTriggerStatuses types.List   `tfsdk:"build_trigger_statuses"`
+	IndexStatuses        types.Map    `tfsdk:"index_statuses"`
+	Indexes              types.Map    `tfsdk:"indexes"`
+	WaitForReady         types.Bool   `tfsdk:"wait_for_ready"`
+	ReadyStatuses        types.List   `tfsdk:"ready_statuses"`
+	Timeout              types.String `tfsdk:"timeout"`
+	MaxIndexesPerBuild   types.Int64  `tfsdk:"max_indexes_per_build"`
+}
+
+// DeferredIndexBuildsKeyspaceModel describes one keyspace and the deferred indexes built in it.
+type DeferredIndexBuildsKeyspaceModel struct {
+	BucketName     types.String `tfsdk:"bucket_name"`
+	ScopeName      types.String `tfsdk:"scope_name"`
+	CollectionName types.String `tfsdk:"collection_name"`
+	IndexNames     types.List   `tfsdk:"index_names"`
+}
+
+func (r *DeferredIndexBuildsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_deferred_index_builds"
+}
+
+func (r *DeferredIndexBuildsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages deferred index builds across several keyspaces of a Couchbase Capella cluster. " +
+			"Behaves like `capellaextras_deferred_index_build`, but accepts any number of bucket, scope and " +
+			"collection groups and records the build status of all their indexes in one map.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				MarkdownDescription: "Composite identifier: `{organization_id}/{project_id}/{cluster_id}/keyspaces/{digest}`, " +
+					"where `digest` is derived from the bucket, scope and collection of every entry in `keyspaces`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"organization_id": schema.StringAttribute{
+				MarkdownDescription: "The organization ID where the indexes are located. Defaults to the provider `organization_id`.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "The project ID where the indexes are located. Defaults to the provider `project_id`.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"cluster_id": schema.StringAttribute{
+				MarkdownDescription: "The cluster ID where the indexes are located.",
+				Required:            true,
+			},
+			"keyspaces": schema.ListNestedAttribute{
+				MarkdownDescription: "The keyspaces whose deferred indexes are built. Each keyspace may only be listed once.",
+				Required:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"bucket_name": schema.StringAttribute{
+							MarkdownDescription: "The bucket where the indexes are located.",
+							Required:            true,
+						},
+						"scope_name": schema.StringAttribute{
+							MarkdownDescription: "The scope where the indexes are located. Defaults to `_default`.",
+							Optional:            true,
+						},
+						"collection_name": schema.StringAttribute{
+							MarkdownDescription: "The collection where the indexes are located. Defaults to `_default`.",
+							Optional:            true,
+						},
+						"index_names": schema.ListAttribute{
+							ElementType:         types.StringType,
+							MarkdownDescription: "The names of the deferred indexes in this keyspace to manage builds for.",
+							Required:            true,
+						},
+					},
+				},
+			},
+			"build_trigger_statuses": schema.ListAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "Index statuses that should trigger a deferred build. " +
+					"Defaults to `[\"Created\"]`.",
+				Optional: true,
+				Computed: true,
+				Default: listdefault.StaticValue(types.ListValueMust(
+					types.StringType,
+					[]attr.Value{types.StringValue("Created")},
+				)),
+			},
+			"index_statuses": schema.MapAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "Current build status of each managed index, keyed by " +
+					"`{bucket_name}/{scope_name}/{collection_name}/{index_name}`. " +
+					"Updated after each apply and refreshed on `terraform plan`.",
+				Computed: true,
+			},
+			"indexes": observedIndexesAttribute("`{bucket_name}/{scope_name}/{collection_name}/{index_name}`"),
+			"wait_for_ready": schema.BoolAttribute{
+				MarkdownDescription: "Wait for every triggered index to reach one of `ready_statuses` before " +
+					"completing the apply. Defaults to `false`, in which case builds run in the background.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"ready_statuses": schema.ListAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "Index statuses that count as a finished build when `wait_for_ready` is `true`. " +
+					"Defaults to `[\"Ready\"]`. An index entering the `Error` status fails the apply.",
+				Optional: true,
+				Computed: true,
+				Default: listdefault.StaticValue(types.ListValueMust(
+					types.StringType,
+					[]attr.Value{types.StringValue("Ready")},
+				)),
+			},
+			"timeout": schema.StringAttribute{
+				MarkdownDescription: "How long to wait for triggered builds in all keyspaces when `wait_for_ready` is `true`, " +
+					"as a Go duration string (e.g. `30m`, `1h`). Defaults to `" + indexwait.DefaultTimeout + "`.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(indexwait.DefaultTimeout),
+			},
+			"max_indexes_per_build": schema.Int64Attribute{
+				MarkdownDescription: "Split the indexes to build in each keyspace into `BUILD INDEX` statements of at most " +
+					"this many indexes. By default each keyspace is built in a single statement.",
+				Optional: true,
+			},
+		},
+	}
+}
+
+func (r *DeferredIndexBuildsResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data DeferredIndexBuildsModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.Timeout.IsNull() && !data.Timeout.IsUnknown() {
+		indexwait.ParseTimeout(data.Timeout, &resp.Diagnostics)
+	}
+
+	if !data.MaxIndexesPerBuild.IsNull() && !data.MaxIndexesPerBuild.IsUnknown() && data.MaxIndexesPerBuild.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_indexes_per_build"),
+			"Invalid Max Indexes Per Build",
+			fmt.Sprintf("max_indexes_per_build must be at least 1, got %d.", data.MaxIndexesPerBuild.ValueInt64()),
+		)
+	}
+
+	if data.Keyspaces.IsNull() || data.Keyspaces.IsUnknown() {
+		return
+	}
+	var keyspaces []DeferredIndexBuildsKeyspaceModel
+	resp.Diagnostics.Append(data.Keyspaces.ElementsAs(ctx, &keyspaces, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Two entries for the same keyspace would race each other's builds and overwrite each
+	// other's statuses, so each keyspace must be listed once.
+	seen := make(map[string]int, len(keyspaces))
+	for i, ks := range keyspaces {
+		if ks.BucketName.IsUnknown() || ks.ScopeName.IsUnknown() || ks.CollectionName.IsUnknown() {
+			continue
+		}
+		scope, collection := keyspace.NameOrDefault(ks.ScopeName), keyspace.NameOrDefault(ks.CollectionName)
+		name := strings.Join([]string{ks.BucketName.ValueString(), scope, collection}, "/")
+		if first, dup := seen[name]; dup {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("keyspaces").AtListIndex(i),
+				"Duplicate Keyspace",
+				fmt.Sprintf("Keyspace %s is already listed at position %d. List all of its indexes in one entry.", name, first+1),
+			)
+			continue
+		}
+		seen[name] = i
+	}
+}
+
+func (r *DeferredIndexBuildsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *apiclient.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+// ModifyPlan fills organization_id and project_id from the provider defaults and marks
+// index_statuses and indexes as unknown — forcing an Update — when a stored status matches
+// build_trigger_statuses or a planned index is absent from index_statuses, exactly as
+// DeferredIndexBuildResource does for a single keyspace.
+func (r *DeferredIndexBuildsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Skip on destroy (no plan).
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	if r.client != nil {
+		providerdefaults.SetProviderDefault(ctx, req, resp, "organization_id", "Organization ID", r.client.OrganizationID)
+		providerdefaults.SetProviderDefault(ctx, req, resp, "project_id", "Project ID", r.client.ProjectID)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var plan DeferredIndexBuildsModel
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	keyspaceNames, keyspacesKnown := plannedKeyspaceNames(ctx, plan.Keyspaces)
+	if plan.OrganizationId.IsUnknown() || plan.ProjectId.IsUnknown() || plan.ClusterId.IsUnknown() || !keyspacesKnown {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), types.StringUnknown())...)
+	} else {
+		id := deferredIndexBuildsID(plan.OrganizationId.ValueString(), plan.ProjectId.ValueString(), plan.ClusterId.ValueString(), keyspaceNames)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), types.StringValue(id))...)
+	}
+
+	// Skip on create (no prior state).
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var state DeferredIndexBuildsModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.BuildTriggerStatuses.IsUnknown() || state.IndexStatuses.IsNull() || state.IndexStatuses.IsUnknown() {
+		return
+	}
+
+	var triggerStatuses []string
+	resp.Diagnostics.Append(plan.BuildTriggerStatuses.ElementsAs(ctx, &triggerStatuses, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	triggerSet := make(map[string]bool, len(triggerStatuses))
+	for _, s := range triggerStatuses {
+		triggerSet[s] = true
+	}
+
+	statusMap := make(map[string]string)
+	resp.Diagnostics.Append(state.IndexStatuses.ElementsAs(ctx, &statusMap, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Check 1: any stored status is a build trigger.
+	for _, status := range statusMap {
+		if triggerSet[status] {
+			planKeyspaceBuilds(ctx, resp)
+			return
+		}
+	}
+
+	// Check 2: any planned index is absent from stored statuses. If some of the keyspaces are
+	// not known yet, they are changing anyway and Terraform will call Update.
+	keyspaces, diags := plannedKeyspaces(ctx, plan.Keyspaces)
+	if diags.HasError() || keyspaces == nil {
+		return
+	}
+	for _, ks := range keyspaces {
+		for _, name := range ks.indexNames {
+			if _, exists := statusMap[ks.key(name)]; !exists {
+				planKeyspaceBuilds(ctx, resp)
+				return
+			}
+		}
+	}
+}
+
+// planKeyspaceBuilds marks index_statuses and indexes as unknown so that Update runs.
+func planKeyspaceBuilds(ctx context.Context, resp *resource.ModifyPlanResponse) {
+	resp.Diagnostics.Append(
+		resp.Plan.SetAttribute(ctx, path.Root("index_statuses"), types.MapUnknown(types.StringType))...,
+	)
+	resp.Diagnostics.Append(
+		resp.Plan.SetAttribute(ctx, path.Root("indexes"), types.MapUnknown(observedIndexType))...,
+	)
+}
+
+func (r *DeferredIndexBuildsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data DeferredIndexBuildsModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save partial progress: once builds were submitted, index_statuses records the ones that
+	// started even if others failed.
+	if submitted := r.performBuild(ctx, &data, types.MapNull(observedIndexType), &resp.Diagnostics); resp.Diagnostics.HasError() && !submitted {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *DeferredIndexBuildsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data DeferredIndexBuildsModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	keyspaces, diags := plannedKeyspaces(ctx, data.Keyspaces)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	builds := newKeyspaceBuilds(r.client, data.OrganizationId, data.ProjectId, data.ClusterId, keyspaces, true)
+	builds.observe(ctx, nil, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	indexStatuses, indexDetails, diags := observedIndexValues(builds.observed, data.Indexes)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.IndexStatuses = indexStatuses
+	data.Indexes = indexDetails
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *DeferredIndexBuildsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data DeferredIndexBuildsModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var prior types.Map
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("indexes"), &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save partial progress: once builds were submitted, index_statuses records the ones that
+	// started even if others failed.
+	if submitted := r.performBuild(ctx, &data, prior, &resp.Diagnostics); resp.Diagnostics.HasError() && !submitted {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Delete is a no-op: this resource does not own the underlying indexes.
+func (r *DeferredIndexBuildsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// There is nothing to delete
+}
+
+// performBuild builds the indexes of every keyspace whose status matches build_trigger_statuses,
+// as described on keyspaceBuilds.run, and stores the statuses in data.IndexStatuses and
+// data.Indexes keyed by keyspace. Indexes whose details match prior keep their last_checked.
+//
+// It reports whether builds were submitted, in which case the caller should save the statuses
+// alongside any errors.
+func (r *DeferredIndexBuildsResource) performBuild(ctx context.Context, data *DeferredIndexBuildsModel, prior types.Map, diagnostics *diag.Diagnostics) (submitted bool) {
+	keyspaces, diags := plannedKeyspaces(ctx, data.Keyspaces)
+	diagnostics.Append(diags...)
+	if diagnostics.HasError() {
+		return false
+	}
+
+	builds := newKeyspaceBuilds(r.client, data.OrganizationId, data.ProjectId, data.ClusterId, keyspaces, true)
+	submitted = builds.run(ctx, buildSettings{
+		BuildTriggerStatuses: data.BuildTriggerStatuses,
+		WaitForReady:         data.WaitForReady,
+		ReadyStatuses:        data.ReadyStatuses,
+		Timeout:              data.Timeout,
+		MaxIndexesPerBuild:   data.MaxIndexesPerBuild,
+	}, diagnostics)
+	if diagnostics.HasError() && !submitted {
+		return false
+	}
+
+	indexStatuses, indexDetails, diags := observedIndexValues(builds.observed, prior)
+	diagnostics.Append(diags...)
+	if diags.HasError() {
+		return false
+	}
+	data.IndexStatuses = indexStatuses
+	data.Indexes = indexDetails
+	keyspaceNames := make([]string, len(keyspaces))
+	for i, ks := range keyspaces {
+		keyspaceNames[i] = ks.String()
+	}
+	data.Id = types.StringValue(deferredIndexBuildsID(data.OrganizationId.ValueString(), data.ProjectId.ValueString(), data.ClusterId.ValueString(), keyspaceNames))
+	return submitted
+}
+
+// plannedKeyspaces converts the keyspaces list into buildKeyspaces. It returns nil without
+// diagnostics when the list or any of its values is not known yet.
+func plannedKeyspaces(ctx context.Context, list types.List) ([]buildKeyspace, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if list.IsNull() || list.IsUnknown() {
+		return nil, diags
+	}
+
+	var models []DeferredIndexBuildsKeyspaceModel
+	diags.Append(list.ElementsAs(ctx, &models, false)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	keyspaces := make([]buildKeyspace, 0, len(models))
+	for _, m := range models {
+		if m.BucketName.IsUnknown() || m.ScopeName.IsUnknown() || m.CollectionName.IsUnknown() || m.IndexNames.IsUnknown() {
+			return nil, diags
+		}
+		ks := buildKeyspace{bucket: m.BucketName.ValueString()}
+		ks.scope, ks.collection = keyspace.NameOrDefault(m.ScopeName), keyspace.NameOrDefault(m.CollectionName)
+		if d := m.IndexNames.ElementsAs(ctx, &ks.indexNames, false); d.HasError() {
+			// Some index names are unknown.
+			return nil, diags
+		}
+		keyspaces = append(keyspaces, ks)
+	}
+	return keyspaces, diags
+}
+
+// plannedKeyspaceNames returns the `{bucket_name}/{scope_name}/{collection_name}` name of each
+// entry of the keyspaces list, or false when the list or any of those names is not known yet.
+func plannedKeyspaceNames(ctx context.Context, list types.List) ([]string, bool) {
+	if list.IsNull() || list.IsUnknown() {
+		return nil, false
+	}
+
+	var models []DeferredIndexBuildsKeyspaceModel
+	if diags := list.ElementsAs(ctx, &models, false); diags.HasError() {
+		return nil, false
+	}
+
+	names := make([]string, 0, len(models))
+	for _, m := range models {
+		if m.BucketName.IsUnknown() || m.ScopeName.IsUnknown() || m.CollectionName.IsUnknown() {
+			return nil, false
+		}
+		scope, collection := keyspace.NameOrDefault(m.ScopeName), keyspace.NameOrDefault(m.CollectionName)
+		names = append(names, strings.Join([]string{m.BucketName.ValueString(), scope, collection}, "/"))
+	}
+	return names, true
+}
+
+// deferredIndexBuildsID returns the composite resource ID
+// `{organization_id}/{project_id}/{cluster_id}/keyspaces/{digest}`. The digest identifies the set
+// of keyspace names, regardless of their order or indexes, so that resources managing different
+// keyspaces of the same cluster get different IDs.
+func deferredIndexBuildsID(organizationId, projectId, clusterId string, keyspaceNames []string) string {
+	sorted := append([]string(nil), keyspaceNames...)
+	sort.Strings(sorted)
+	sum := sha256.Sum256([]byte(strings.Join(sorted, "\n")))
+	return strings.Join([]string{
+		organizationId,
+		projectId,
+		clusterId,
+		deferredIndexBuildsIDMarker,
+		hex.EncodeToString(sum[:8]),
+	}, "/")
+}
@@ -0,0 +1,234 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
+	"github.com/cdsre/terraform-provider-capellaextras/api/indexes"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/indexwait"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// buildKeyspace is a keyspace with its scope and collection defaults resolved, and the indexes
+// managed in it.
+type buildKeyspace struct {
+	bucket, scope, collection string
+	indexNames                []string
+}
+
+// key returns the index_statuses and indexes key of indexName in the keyspace.
+func (ks buildKeyspace) key(indexName string) string {
+	return strings.Join([]string{ks.bucket, ks.scope, ks.collection, indexName}, "/")
+}
+
+func (ks buildKeyspace) String() string {
+	return strings.Join([]string{ks.bucket, ks.scope, ks.collection}, "/")
+}
+
+// buildSettings are the attributes that control how the deferred index build resources build
+// and wait for indexes.
+type buildSettings struct {
+	BuildTriggerStatuses types.List
+	WaitForReady         types.Bool
+	ReadyStatuses        types.List
+	Timeout              types.String
+	MaxIndexesPerBuild   types.Int64
+}
+
+// keyspaceBuilds observes, builds and waits for the deferred indexes of keyspaces in one cluster,
+// recording the last observed status of each index. DeferredIndexBuildResource uses it for its
+// single keyspace and DeferredIndexBuildsResource for each of its keyspaces.
+type keyspaceBuilds struct {
+	client                               *apiclient.Client
+	organizationId, projectId, clusterId string
+	keyspaces                            []buildKeyspace
+	// qualified keys the observed indexes by keyspace and names the keyspace in diagnostics, for
+	// resources that manage more than one keyspace.
+	qualified bool
+	observed  map[string]*observedIndex
+}
+
+func newKeyspaceBuilds(client *apiclient.Client, organizationId, projectId, clusterId types.String, keyspaces []buildKeyspace, qualified bool) *keyspaceBuilds {
+	return &keyspaceBuilds{
+		client:         client,
+		organizationId: organizationId.ValueString(),
+		projectId:      projectId.ValueString(),
+		clusterId:      clusterId.ValueString(),
+		keyspaces:      keyspaces,
+		qualified:      qualified,
+		observed:       make(map[string]*observedIndex),
+	}
+}
+
+// key returns the observed key of indexName in ks.
+func (b *keyspaceBuilds) key(ks buildKeyspace, indexName string) string {
+	if b.qualified {
+		return ks.key(indexName)
+	}
+	return indexName
+}
+
+// addError reports an error about ks, naming the keyspace when there may be several.
+func (b *keyspaceBuilds) addError(ks buildKeyspace, summary, detail string, diagnostics *diag.Diagnostics) {
+	if b.qualified {
+		detail = fmt.Sprintf("Keyspace %s: %s", ks, detail)
+	}
+	diagnostics.AddError(summary, detail)
+}
+
+// run builds the indexes whose status matches build_trigger_statuses and, when wait_for_ready is
+// set, waits for them under a single timeout. Triggered indexes are recorded as "Building"
+// without an extra API call unless the wait records their final status instead.
+//
+// It reports whether builds were submitted. If so, the observed statuses reflect the builds that
+// started even when others failed, and the caller should save them alongside the errors.
+func (b *keyspaceBuilds) run(ctx context.Context, settings buildSettings, diagnostics *diag.Diagnostics) (submitted bool) {
+	var triggerStatuses []string
+	diagnostics.Append(settings.BuildTriggerStatuses.ElementsAs(ctx, &triggerStatuses, false)...)
+	if diagnostics.HasError() {
+		return false
+	}
+
+	toBuild := b.observe(ctx, triggerStatuses, diagnostics)
+	if diagnostics.HasError() {
+		return false
+	}
+
+	submitted, built := b.build(ctx, toBuild, settings.MaxIndexesPerBuild, diagnostics)
+	if built && settings.WaitForReady.ValueBool() {
+		b.wait(ctx, toBuild, settings, diagnostics)
+	}
+	return submitted
+}
+
+// observe fetches the build statuses of the indexes of every keyspace, omitting indexes that do
+// not exist yet, and records them. It returns the indexes of each keyspace whose status is in
+// triggerStatuses, in index_names order so BUILD INDEX statements keep the configured order.
+// Every keyspace is fetched before any is built, so a failure here leaves nothing half done.
+func (b *keyspaceBuilds) observe(ctx context.Context, triggerStatuses []string, diagnostics *diag.Diagnostics) [][]string {
+	triggerSet := make(map[string]bool, len(triggerStatuses))
+	for _, s := range triggerStatuses {
+		triggerSet[s] = true
+	}
+
+	toBuild := make([][]string, len(b.keyspaces))
+	for i, ks := range b.keyspaces {
+		details, err := indexes.GetIndexBuildStatusDetails(ctx, b.client, &indexes.IndexBuildStatusesRequest{
+			OrganizationId: b.organizationId,
+			ProjectId:      b.projectId,
+			ClusterId:      b.clusterId,
+			Bucket:         ks.bucket,
+			Scope:          ks.scope,
+			Collection:     ks.collection,
+			IndexNames:     ks.indexNames,
+		})
+		if err != nil {
+			b.addError(ks, "Get Index Build Status Failed", fmt.Sprintf("Cannot get index build statuses: %v", err), diagnostics)
+			return nil
+		}
+
+		checked := time.Now()
+		for _, indexName := range ks.indexNames {
+			res, ok := details[indexName]
+			if !ok {
+				continue
+			}
+			b.observed[b.key(ks, indexName)] = newObservedIndex(res, checked)
+			if triggerSet[res.Status] {
+				toBuild[i] = append(toBuild[i], indexName)
+			}
+		}
+	}
+	return toBuild
+}
+
+// build submits BUILD INDEX statements for toBuild[i] in each keyspaces[i], one keyspace after
+// another, and records the indexes whose builds started as "Building". A keyspace whose build
+// fails does not stop the others. It reports whether any builds were submitted and whether every
+// submitted build started.
+func (b *keyspaceBuilds) build(ctx context.Context, toBuild [][]string, maxIndexesPerBuild types.Int64, diagnostics *diag.Diagnostics) (submitted, ok bool) {
+	ok = true
+	for i, ks := range b.keyspaces {
+		if len(toBuild[i]) == 0 {
+			continue
+		}
+		res, err := indexes.BuildDeferredIndexes(ctx, b.client, &indexes.IndexBuildRequest{
+			OrganizationId:     b.organizationId,
+			ProjectId:          b.projectId,
+			ClusterId:          b.clusterId,
+			Bucket:             ks.bucket,
+			Scope:              ks.scope,
+			Collection:         ks.collection,
+			IndexNames:         toBuild[i],
+			MaxIndexesPerBuild: int(maxIndexesPerBuild.ValueInt64()),
+		})
+		submitted = true
+
+		// Reflect the triggered builds in state without an extra API call.
+		// Read() will correct this to the real status on the next plan refresh.
+		built := time.Now()
+		for _, idx := range res.Built {
+			b.observed[b.key(ks, idx)].setStatus("Building", built)
+		}
+
+		if err != nil {
+			ok = false
+			var buildDiags diag.Diagnostics
+			addBuildFailureDiagnostics(res, &buildDiags)
+			for _, d := range buildDiags {
+				b.addError(ks, d.Summary(), d.Detail(), diagnostics)
+			}
+		}
+	}
+	return submitted, ok
+}
+
+// wait blocks until the indexes in toBuild[i] of each keyspaces[i] reach one of ready_statuses,
+// recording each observed status. It fails if an index errors or the timeout, which covers all
+// keyspaces, elapses.
+func (b *keyspaceBuilds) wait(ctx context.Context, toBuild [][]string, settings buildSettings, diagnostics *diag.Diagnostics) {
+	var readyStatuses []string
+	diagnostics.Append(settings.ReadyStatuses.ElementsAs(ctx, &readyStatuses, false)...)
+	if diagnostics.HasError() {
+		return
+	}
+
+	timeout := indexwait.ParseTimeout(settings.Timeout, diagnostics)
+	if diagnostics.HasError() {
+		return
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for i, ks := range b.keyspaces {
+		if len(toBuild[i]) == 0 {
+			continue
+		}
+		statuses, err := indexes.WaitForIndexBuild(waitCtx, b.client, &indexes.WaitForIndexBuildRequest{
+			OrganizationId: b.organizationId,
+			ProjectId:      b.projectId,
+			ClusterId:      b.clusterId,
+			Bucket:         ks.bucket,
+			Scope:          ks.scope,
+			Collection:     ks.collection,
+			IndexNames:     toBuild[i],
+			ReadyStatuses:  readyStatuses,
+		})
+		checked := time.Now()
+		for idx, status := range statuses {
+			b.observed[b.key(ks, idx)].setStatus(status, checked)
+		}
+		if err != nil {
+			indexwait.AddFailureDiagnostics(err, timeout, diagnostics)
+			return
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package resources
+
+import (
+	"time"
+
+	"github.com/cdsre/terraform-provider-capellaextras/api/indexes"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// observedIndexType is the type of an entry in the indexes attribute.
+var observedIndexType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"status":       types.StringType,
+	"last_checked": types.StringType,
+	"progress":     types.Int64Type,
+	"num_replica":  types.Int64Type,
+	"hosts":        types.ListType{ElemType: types.StringType},
+	"error":        types.StringType,
+}}
+
+// observedIndexesAttribute returns the schema of the computed indexes attribute, whose keys are
+// described by keyDescription.
+func observedIndexesAttribute(keyDescription string) schema.MapNestedAttribute {
+	return schema.MapNestedAttribute{
+		MarkdownDescription: "Build details of each managed index, keyed by " + keyDescription + ". " +
+			"Fields the API does not report are null. Updated after each apply and refreshed on `terraform plan`.",
+		Computed: true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"status": schema.StringAttribute{
+					MarkdownDescription: "The build status of the index, as in `index_statuses`.",
+					Computed:            true,
+				},
+				"last_checked": schema.StringAttribute{
+					MarkdownDescription: "When the details of the index last changed, as read from the API or set by a build this resource triggered, " +
+						"as an RFC 3339 timestamp. Refreshes that observe the same details keep the earlier timestamp.",
+					Computed: true,
+				},
+				"progress": schema.Int64Attribute{
+					MarkdownDescription: "The build progress of the index as a percentage.",
+					Computed:            true,
+				},
+				"num_replica": schema.Int64Attribute{
+					MarkdownDescription: "The number of replicas of the index.",
+					Computed:            true,
+				},
+				"hosts": schema.ListAttribute{
+					ElementType:         types.StringType,
+					MarkdownDescription: "The index nodes hosting the index and its replicas.",
+					Computed:            true,
+				},
+				"error": schema.StringAttribute{
+					MarkdownDescription: "The error reported for the index, if any.",
+					Computed:            true,
+				},
+			},
+		},
+	}
+}
+
+// observedIndex is the last observed build status of an index.
+type observedIndex struct {
+	indexes.IndexBuildStatusResponse
+	checked time.Time
+}
+
+func newObservedIndex(res *indexes.IndexBuildStatusResponse, at time.Time) *observedIndex {
+	return &observedIndex{IndexBuildStatusResponse: *res, checked: at}
+}
+
+// setStatus records a status known without a full status response, such as "Building" after
+// triggering a build. Progress and errors no longer apply; replicas and hosts are kept, as a
+// build does not change them.
+func (o *observedIndex) setStatus(status string, at time.Time) {
+	o.Status = status
+	o.Progress = nil
+	o.Error = ""
+	o.checked = at
+}
+
+func (o *observedIndex) value() attr.Value {
+	hosts := types.ListNull(types.StringType)
+	if o.Hosts != nil {
+		elems := make([]attr.Value, len(o.Hosts))
+		for i, h := range o.Hosts {
+			elems[i] = types.StringValue(h)
+		}
+		hosts = types.ListValueMust(types.StringType, elems)
+	}
+	errMsg := types.StringNull()
+	if o.Error != "" {
+		errMsg = types.StringValue(o.Error)
+	}
+	return types.ObjectValueMust(observedIndexType.AttrTypes, map[string]attr.Value{
+		"status":       types.StringValue(o.Status),
+		"last_checked": types.StringValue(o.checked.UTC().Format(time.RFC3339)),
+		"progress":     types.Int64PointerValue(o.Progress),
+		"num_replica":  types.Int64PointerValue(o.NumReplica),
+		"hosts":        hosts,
+		"error":        errMsg,
+	})
+}
+
+// observedIndexValues returns the index_statuses and indexes attributes for observed. An index
+// whose details match its entry in prior, the indexes attribute from state, keeps that entry, so
+// last_checked only moves when something about the index changed.
+func observedIndexValues(observed map[string]*observedIndex, prior types.Map) (statuses, details types.Map, diags diag.Diagnostics) {
+	priorDetails := prior.Elements()
+	statusMap := make(map[string]attr.Value, len(observed))
+	detailMap := make(map[string]attr.Value, len(observed))
+	for key, o := range observed {
+		statusMap[key] = types.StringValue(o.Status)
+		detailMap[key] = o.value()
+		if p, ok := priorDetails[key]; ok && sameObservation(p, detailMap[key]) {
+			detailMap[key] = p
+		}
+	}
+
+	statuses, d := types.MapValue(types.StringType, statusMap)
+	diags.Append(d...)
+	details, d = types.MapValue(observedIndexType, detailMap)
+	diags.Append(d...)
+	return statuses, details, diags
+}
+
+// sameObservation reports whether two indexes entries hold the same details, ignoring last_checked.
+func sameObservation(a, b attr.Value) bool {
+	ao, ok := a.(types.Object)
+	if !ok || ao.IsNull() || ao.IsUnknown() {
+		return false
+	}
+	bAttrs := b.(types.Object).Attributes()
+	for name, v := range ao.Attributes() {
+		if name != "last_checked" && !v.Equal(bAttrs[name]) {
+			return false
+		}
+	}
+	return true
+}
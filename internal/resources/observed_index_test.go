@@ -0,0 +1,40 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package resources
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cdsre/terraform-provider-capellaextras/api/indexes"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestObservedIndexValues_KeepsLastCheckedWhenUnchanged(t *testing.T) {
+	first := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, prior, diags := observedIndexValues(map[string]*observedIndex{
+		"idx1": newObservedIndex(&indexes.IndexBuildStatusResponse{Status: "Ready"}, first),
+		"idx2": newObservedIndex(&indexes.IndexBuildStatusResponse{Status: "Created"}, first),
+	}, types.MapNull(observedIndexType))
+	if diags.HasError() {
+		t.Fatalf("observedIndexValues() diags = %v", diags)
+	}
+
+	later := first.Add(time.Hour)
+	_, details, diags := observedIndexValues(map[string]*observedIndex{
+		"idx1": newObservedIndex(&indexes.IndexBuildStatusResponse{Status: "Ready"}, later),
+		"idx2": newObservedIndex(&indexes.IndexBuildStatusResponse{Status: "Building"}, later),
+	}, prior)
+	if diags.HasError() {
+		t.Fatalf("observedIndexValues() diags = %v", diags)
+	}
+
+	want := map[string]time.Time{"idx1": first, "idx2": later}
+	for key, at := range want {
+		got := details.Elements()[key].(types.Object).Attributes()["last_checked"]
+		if !got.Equal(types.StringValue(at.Format(time.RFC3339))) {
+			t.Errorf("%s last_checked = %s, want %s", key, got, at.Format(time.RFC3339))
+		}
+	}
+}
@@ -0,0 +1,693 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apiclient "github.com/cdsre/terraform-provider-capellaextras/api/client"
+	"github.com/cdsre/terraform-provider-capellaextras/api/indexes"
+	"github.com/cdsre/terraform-provider-capellaextras/api/indexes/n1ql"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/keyspace"
+	"github.com/cdsre/terraform-provider-capellaextras/internal/providerdefaults"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.Resource = &QueryIndexResource{}
+var _ resource.ResourceWithConfigure = &QueryIndexResource{}
+var _ resource.ResourceWithModifyPlan = &QueryIndexResource{}
+var _ resource.ResourceWithValidateConfig = &QueryIndexResource{}
+var _ resource.ResourceWithImportState = &QueryIndexResource{}
+
+// queryIndexImportedPrivateKey marks state written by ImportState, so the first Read also fills
+// in the `with` options the index was created with.
+const queryIndexImportedPrivateKey = "imported"
+
+// queryIndexIdentity lists the attributes that identify the index. Changing any of them
+// replaces the resource; every other argument is applied by dropping and recreating the index.
+var queryIndexIdentity = []string{
+	"organization_id", "project_id", "cluster_id", "bucket_name", "scope_name", "collection_name", "index_name",
+}
+
+func NewQueryIndexResource() resource.Resource {
+	return &QueryIndexResource{}
+}
+
+// QueryIndexResource manages a single GSI query index.
+type QueryIndexResource struct {
+	client *apiclient.Client
+}
+
+// QueryIndexModel describes the resource data model.
+type QueryIndexModel struct {
+	Id             types.String         `tfsdk:"id"`
+	OrganizationId types.String         `tfsdk:"organization_id"`
+	ProjectId      types.String         `tfsdk:"project_id"`
+	ClusterId      types.String         `tfsdk:"cluster_id"`
+	BucketName     types.String         `tfsdk:"bucket_name"`
+	ScopeName      types.String         `tfsdk:"scope_name"`
+	CollectionName types.String         `tfsdk:"collection_name"`
+	IndexName      types.String         `tfsdk:"index_name"`
+	IsPrimary      types.Bool           `tfsdk:"is_primary"`
+	IndexKeys      types.List           `tfsdk:"index_keys"`
+	Where          types.String         `tfsdk:"where"`
+	PartitionBy    types.String         `tfsdk:"partition_by"`
+	With           *QueryIndexWithModel `tfsdk:"with"`
+	Status         types.String         `tfsdk:"status"`
+}
+
+// QueryIndexWithModel describes the `with` options of the index.
+type QueryIndexWithModel struct {
+	DeferBuild   types.Bool  `tfsdk:"defer_build"`
+	NumReplica   types.Int64 `tfsdk:"num_replica"`
+	NumPartition types.Int64 `tfsdk:"num_partition"`
+	Nodes        types.List  `tfsdk:"nodes"`
+}
+
+func (r *QueryIndexResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_query_index"
+}
+
+func (r *QueryIndexResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a Couchbase Capella GSI query index through the query service API. " +
+			"Changing the definition of an existing index drops and recreates it under the same name.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				MarkdownDescription: "Composite identifier: `{organization_id}/{project_id}/{cluster_id}/{bucket_name}/{scope_name}/{collection_name}/{index_name}`, " +
+					"using `_default` for an unset scope or collection.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"organization_id": schema.StringAttribute{
+				MarkdownDescription: "The organization ID where the index is located. Defaults to the provider `organization_id`.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "The project ID where the index is located. Defaults to the provider `project_id`.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"cluster_id": schema.StringAttribute{
+				MarkdownDescription: "The cluster ID where the index is located.",
+				Required:            true,
+			},
+			"bucket_name": schema.StringAttribute{
+				MarkdownDescription: "The bucket to index.",
+				Required:            true,
+			},
+			"scope_name": schema.StringAttribute{
+				MarkdownDescription: "The scope to index. Defaults to `_default`.",
+				Optional:            true,
+			},
+			"collection_name": schema.StringAttribute{
+				MarkdownDescription: "The collection to index. Defaults to `_default`.",
+				Optional:            true,
+			},
+			"index_name": schema.StringAttribute{
+				MarkdownDescription: "The name of the index.",
+				Required:            true,
+			},
+			"is_primary": schema.BoolAttribute{
+				MarkdownDescription: "Create a primary index. Defaults to `false`. " +
+					"A primary index cannot have `index_keys`, `where` or `partition_by`.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"index_keys": schema.ListAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "The index key expressions, used verbatim, e.g. `` `airline` `` or " +
+					"`` DISTINCT ARRAY `s`.`day` FOR `s` IN `schedule` END `` for an array index. " +
+					"Required unless `is_primary` is `true`.",
+				Optional: true,
+			},
+			"where": schema.StringAttribute{
+				MarkdownDescription: "The condition of a partial index, without the `WHERE` keyword.",
+				Optional:            true,
+			},
+			"partition_by": schema.StringAttribute{
+				MarkdownDescription: "The partitioning expression of a partitioned index, without the `PARTITION BY` keywords, " +
+					"e.g. `HASH(meta().id)`.",
+				Optional: true,
+			},
+			"with": schema.SingleNestedAttribute{
+				MarkdownDescription: "Options passed in the `WITH` clause of the `CREATE INDEX` statement.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"defer_build": schema.BoolAttribute{
+						MarkdownDescription: "Create the index without building it. " +
+							"Build it later with `capellaextras_deferred_index_build` or the `capellaextras_build_index` action.",
+						Optional: true,
+					},
+					"num_replica": schema.Int64Attribute{
+						MarkdownDescription: "The number of index replicas.",
+						Optional:            true,
+					},
+					"num_partition": schema.Int64Attribute{
+						MarkdownDescription: "The number of partitions of a partitioned index.",
+						Optional:            true,
+					},
+					"nodes": schema.ListAttribute{
+						ElementType:         types.StringType,
+						MarkdownDescription: "The index nodes to place the index and its replicas on, as `host:port`.",
+						Optional:            true,
+					},
+				},
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "The build status of the index, e.g. `Created`, `Building` or `Ready`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *QueryIndexResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var isPrimary types.Bool
+	var indexKeys types.List
+	var where, partitionBy types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("is_primary"), &isPrimary)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("index_keys"), &indexKeys)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("where"), &where)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("partition_by"), &partitionBy)...)
+	if resp.Diagnostics.HasError() || isPrimary.IsUnknown() {
+		return
+	}
+
+	if isPrimary.ValueBool() {
+		for _, set := range []struct {
+			name  string
+			isSet bool
+		}{
+			{"index_keys", !indexKeys.IsNull()},
+			{"where", !where.IsNull()},
+			{"partition_by", !partitionBy.IsNull()},
+		} {
+			if set.isSet {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(set.name),
+					"Invalid Primary Index",
+					fmt.Sprintf("%s cannot be set on a primary index.", set.name),
+				)
+			}
+		}
+		return
+	}
+
+	if indexKeys.IsNull() || (!indexKeys.IsUnknown() && len(indexKeys.Elements()) == 0) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("index_keys"),
+			"Missing Index Keys",
+			"index_keys must contain at least one key unless is_primary is true.",
+		)
+	}
+}
+
+func (r *QueryIndexResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *apiclient.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+// ModifyPlan fills organization_id and project_id from the provider defaults, plans the id,
+// and requires replacement when any attribute identifying the index changes. Any other change
+// recreates the index, so its status is planned as unknown.
+func (r *QueryIndexResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Skip on destroy (no plan).
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	if r.client != nil {
+		providerdefaults.SetProviderDefault(ctx, req, resp, "organization_id", "Organization ID", r.client.OrganizationID)
+		providerdefaults.SetProviderDefault(ctx, req, resp, "project_id", "Project ID", r.client.ProjectID)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var plan QueryIndexModel
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if queryIndexIdentityKnown(&plan) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), types.StringValue(queryIndexID(&plan)))...)
+	} else {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), types.StringUnknown())...)
+	}
+
+	// Skip on create (no prior state).
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	for _, name := range queryIndexIdentity {
+		var planned, prior types.String
+		resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root(name), &planned)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root(name), &prior)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if !planned.Equal(prior) {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root(name))
+		}
+	}
+
+	if !resp.Plan.Raw.Equal(req.State.Raw) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("status"), types.StringUnknown())...)
+	}
+}
+
+func (r *QueryIndexResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data QueryIndexModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.createIndex(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read refreshes the index definition and status, and removes the resource from state when the
+// index no longer exists. The query service normalises expressions, for example quoting
+// identifiers and adding parentheses, so a key, condition or partitioning expression equivalent
+// to the one in state keeps its state spelling and only a real change is reported as drift.
+// Options in `with` are only refreshed when set: the query service applies its own defaults to
+// the others. defer_build is only read on import, and nodes is never read back.
+func (r *QueryIndexResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data QueryIndexModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	imported, diags := req.Private.GetKey(ctx, queryIndexImportedPrivateKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	scope, collection := queryIndexKeyspace(&data)
+	idx, err := indexes.GetIndex(ctx, r.client, &indexes.GetIndexRequest{
+		OrganizationId: data.OrganizationId.ValueString(),
+		ProjectId:      data.ProjectId.ValueString(),
+		ClusterId:      data.ClusterId.ValueString(),
+		Bucket:         data.BucketName.ValueString(),
+		Scope:          scope,
+		Collection:     collection,
+		IndexName:      data.IndexName.ValueString(),
+	})
+	if err != nil {
+		if apiclient.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Get Index Failed",
+			fmt.Sprintf("Cannot read index %q: %v", data.IndexName.ValueString(), err),
+		)
+		return
+	}
+
+	refreshQueryIndex(&data, idx, len(imported) > 0)
+	if data.Status.ValueString() == "" {
+		res, err := indexes.GetIndexBuildStatus(ctx, r.client, &indexes.IndexBuildStatusRequest{
+			OrganizationId: data.OrganizationId.ValueString(),
+			ProjectId:      data.ProjectId.ValueString(),
+			ClusterId:      data.ClusterId.ValueString(),
+			Bucket:         data.BucketName.ValueString(),
+			IndexName:      data.IndexName.ValueString(),
+			Scope:          scope,
+			Collection:     collection,
+		})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Get Index Build Status Failed",
+				fmt.Sprintf("Cannot get build status for index %q: %v", data.IndexName.ValueString(), err),
+			)
+			return
+		}
+		data.Status = types.StringValue(res.Status)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if len(imported) > 0 {
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, queryIndexImportedPrivateKey, nil)...)
+	}
+}
+
+// ImportState imports an existing index by its ID,
+// `{organization_id}/{project_id}/{cluster_id}/{bucket_name}/{scope_name}/{collection_name}/{index_name}`.
+// The definition is filled in by the Read that follows.
+func (r *QueryIndexResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts := strings.Split(req.ID, "/")
+	if len(parts) != 7 {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form "+
+				"organization_id/project_id/cluster_id/bucket_name/scope_name/collection_name/index_name, got: %q", req.ID),
+		)
+		return
+	}
+	for i, part := range parts {
+		if part == "" {
+			resp.Diagnostics.AddError(
+				"Unexpected Import Identifier",
+				fmt.Sprintf("Import identifier %q has an empty component at position %d.", req.ID, i+1),
+			)
+			return
+		}
+	}
+
+	data := QueryIndexModel{
+		OrganizationId: types.StringValue(parts[0]),
+		ProjectId:      types.StringValue(parts[1]),
+		ClusterId:      types.StringValue(parts[2]),
+		BucketName:     types.StringValue(parts[3]),
+		// The default scope and collection are stored as null so that configurations
+		// which omit scope_name/collection_name import without a diff.
+		ScopeName:      optionalKeyspaceName(parts[4]),
+		CollectionName: optionalKeyspaceName(parts[5]),
+		IndexName:      types.StringValue(parts[6]),
+		IsPrimary:      types.BoolValue(false),
+		IndexKeys:      types.ListNull(types.StringType),
+		Where:          types.StringNull(),
+		PartitionBy:    types.StringNull(),
+		Status:         types.StringNull(),
+	}
+	data.Id = types.StringValue(queryIndexID(&data))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, queryIndexImportedPrivateKey, []byte(`true`))...)
+}
+
+// Update drops the index and recreates it with the planned definition. Indexes cannot be
+// altered in place, and ModifyPlan forces a replacement for any change to the index identity.
+// A plan that only respells the expressions of the index, as after an import, is stored without
+// recreating it.
+func (r *QueryIndexResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, prior QueryIndexModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if equivalentQueryIndexDefinition(&prior, &data) {
+		data.Status = prior.Status
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	r.dropIndex(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.createIndex(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *QueryIndexResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data QueryIndexModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.dropIndex(ctx, &data, &resp.Diagnostics)
+}
+
+// createIndex creates the index described by data and records its initial status.
+func (r *QueryIndexResource) createIndex(ctx context.Context, data *QueryIndexModel, diagnostics *diag.Diagnostics) {
+	createReq := &indexes.CreateIndexRequest{
+		OrganizationId: data.OrganizationId.ValueString(),
+		ProjectId:      data.ProjectId.ValueString(),
+		ClusterId:      data.ClusterId.ValueString(),
+		Bucket:         data.BucketName.ValueString(),
+		IndexName:      data.IndexName.ValueString(),
+		IsPrimary:      data.IsPrimary.ValueBool(),
+		Where:          data.Where.ValueString(),
+		PartitionBy:    data.PartitionBy.ValueString(),
+	}
+	createReq.Scope, createReq.Collection = queryIndexKeyspace(data)
+
+	if !data.IndexKeys.IsNull() {
+		diagnostics.Append(data.IndexKeys.ElementsAs(ctx, &createReq.Keys, false)...)
+	}
+	if data.With != nil {
+		createReq.With = &indexes.IndexWith{
+			DeferBuild:   data.With.DeferBuild.ValueBoolPointer(),
+			NumReplica:   data.With.NumReplica.ValueInt64Pointer(),
+			NumPartition: data.With.NumPartition.ValueInt64Pointer(),
+		}
+		if !data.With.Nodes.IsNull() {
+			diagnostics.Append(data.With.Nodes.ElementsAs(ctx, &createReq.With.Nodes, false)...)
+		}
+	}
+	if diagnostics.HasError() {
+		return
+	}
+
+	if _, err := indexes.CreateIndex(ctx, r.client, createReq); err != nil {
+		diagnostics.AddError(
+			"Create Index Failed",
+			fmt.Sprintf("Cannot create index %q: %v", data.IndexName.ValueString(), err),
+		)
+		return
+	}
+
+	res, err := indexes.GetIndexBuildStatus(ctx, r.client, &indexes.IndexBuildStatusRequest{
+		OrganizationId: createReq.OrganizationId,
+		ProjectId:      createReq.ProjectId,
+		ClusterId:      createReq.ClusterId,
+		Bucket:         createReq.Bucket,
+		IndexName:      createReq.IndexName,
+		Scope:          createReq.Scope,
+		Collection:     createReq.Collection,
+	})
+	if err != nil {
+		diagnostics.AddError(
+			"Get Index Build Status Failed",
+			fmt.Sprintf("Index %q was created, but its build status cannot be read: %v", data.IndexName.ValueString(), err),
+		)
+		return
+	}
+	data.Status = types.StringValue(res.Status)
+	data.Id = types.StringValue(queryIndexID(data))
+}
+
+// dropIndex drops the index described by data. An index that no longer exists is not an error.
+func (r *QueryIndexResource) dropIndex(ctx context.Context, data *QueryIndexModel, diagnostics *diag.Diagnostics) {
+	scope, collection := queryIndexKeyspace(data)
+	err := indexes.DropIndex(ctx, r.client, &indexes.DropIndexRequest{
+		OrganizationId: data.OrganizationId.ValueString(),
+		ProjectId:      data.ProjectId.ValueString(),
+		ClusterId:      data.ClusterId.ValueString(),
+		Bucket:         data.BucketName.ValueString(),
+		IndexName:      data.IndexName.ValueString(),
+		Scope:          scope,
+		Collection:     collection,
+	})
+	if err != nil && !apiclient.IsNotFound(err) {
+		diagnostics.AddError(
+			"Drop Index Failed",
+			fmt.Sprintf("Cannot drop index %q: %v", data.IndexName.ValueString(), err),
+		)
+	}
+}
+
+// queryIndexID returns the composite resource ID for the index in data.
+func queryIndexID(data *QueryIndexModel) string {
+	scope, collection := queryIndexKeyspace(data)
+	return strings.Join([]string{
+		data.OrganizationId.ValueString(),
+		data.ProjectId.ValueString(),
+		data.ClusterId.ValueString(),
+		data.BucketName.ValueString(),
+		scope,
+		collection,
+		data.IndexName.ValueString(),
+	}, "/")
+}
+
+// queryIndexIdentityKnown reports whether every attribute that makes up the resource ID is known.
+func queryIndexIdentityKnown(data *QueryIndexModel) bool {
+	for _, v := range []types.String{
+		data.OrganizationId, data.ProjectId, data.ClusterId, data.BucketName, data.ScopeName, data.CollectionName, data.IndexName,
+	} {
+		if v.IsUnknown() {
+			return false
+		}
+	}
+	return true
+}
+
+func queryIndexKeyspace(data *QueryIndexModel) (scope, collection string) {
+	return keyspace.NameOrDefault(data.ScopeName), keyspace.NameOrDefault(data.CollectionName)
+}
+
+// refreshQueryIndex updates data from the index properties read from the API. When imported is
+// set, the `with` options are filled in from the index rather than only refreshed.
+func refreshQueryIndex(data *QueryIndexModel, idx *indexes.Index, imported bool) {
+	data.Status = types.StringValue(idx.Status)
+	data.IsPrimary = types.BoolValue(idx.IsPrimary)
+	if idx.IsPrimary {
+		data.IndexKeys = types.ListNull(types.StringType)
+		data.Where = types.StringNull()
+		data.PartitionBy = types.StringNull()
+	} else {
+		data.IndexKeys = refreshedIndexKeys(data.IndexKeys, idx.SecondaryExprs)
+		data.Where = refreshedIndexExpr(data.Where, idx.Where)
+		data.PartitionBy = refreshedIndexExpr(data.PartitionBy, idx.PartitionBy)
+	}
+
+	if imported {
+		with := &QueryIndexWithModel{
+			DeferBuild:   types.BoolNull(),
+			NumReplica:   types.Int64Null(),
+			NumPartition: types.Int64Null(),
+			Nodes:        types.ListNull(types.StringType),
+		}
+		if idx.DeferBuild {
+			with.DeferBuild = types.BoolValue(true)
+		}
+		if idx.NumReplica > 0 {
+			with.NumReplica = types.Int64Value(idx.NumReplica)
+		}
+		if idx.NumPartition > 0 {
+			with.NumPartition = types.Int64Value(idx.NumPartition)
+		}
+		data.With = nil
+		if !with.DeferBuild.IsNull() || !with.NumReplica.IsNull() || !with.NumPartition.IsNull() {
+			data.With = with
+		}
+		return
+	}
+	if data.With != nil {
+		if !data.With.NumReplica.IsNull() {
+			data.With.NumReplica = types.Int64Value(idx.NumReplica)
+		}
+		if !data.With.NumPartition.IsNull() {
+			data.With.NumPartition = types.Int64Value(idx.NumPartition)
+		}
+	}
+}
+
+// equivalentQueryIndexDefinition reports whether a and b define the same index, treating
+// equivalent expressions as equal.
+func equivalentQueryIndexDefinition(a, b *QueryIndexModel) bool {
+	if !a.IsPrimary.Equal(b.IsPrimary) || !equivalentIndexExprValue(a.Where, b.Where) || !equivalentIndexExprValue(a.PartitionBy, b.PartitionBy) {
+		return false
+	}
+	aKeys, bKeys := a.IndexKeys.Elements(), b.IndexKeys.Elements()
+	if a.IndexKeys.IsUnknown() || b.IndexKeys.IsUnknown() || len(aKeys) != len(bKeys) {
+		return false
+	}
+	for i := range aKeys {
+		aKey, aOK := aKeys[i].(types.String)
+		bKey, bOK := bKeys[i].(types.String)
+		if !aOK || !bOK || !equivalentIndexExprValue(aKey, bKey) {
+			return false
+		}
+	}
+	if a.With == nil || b.With == nil {
+		return a.With == nil && b.With == nil
+	}
+	return a.With.DeferBuild.Equal(b.With.DeferBuild) &&
+		a.With.NumReplica.Equal(b.With.NumReplica) &&
+		a.With.NumPartition.Equal(b.With.NumPartition) &&
+		a.With.Nodes.Equal(b.With.Nodes)
+}
+
+// equivalentIndexExprValue is equivalentIndexExpr for known values, treating null as empty.
+func equivalentIndexExprValue(a, b types.String) bool {
+	if a.IsUnknown() || b.IsUnknown() {
+		return false
+	}
+	return equivalentIndexExpr(a.ValueString(), b.ValueString())
+}
+
+// refreshedIndexKeys returns the index keys in state when they are equivalent to the keys read
+// from the API, and the keys read otherwise.
+func refreshedIndexKeys(prior types.List, keys []string) types.List {
+	if !prior.IsNull() && !prior.IsUnknown() && len(prior.Elements()) == len(keys) {
+		same := true
+		for i, elem := range prior.Elements() {
+			if s, ok := elem.(types.String); !ok || !equivalentIndexExpr(s.ValueString(), keys[i]) {
+				same = false
+				break
+			}
+		}
+		if same {
+			return prior
+		}
+	}
+	if len(keys) == 0 {
+		return types.ListNull(types.StringType)
+	}
+	elems := make([]attr.Value, len(keys))
+	for i, k := range keys {
+		elems[i] = types.StringValue(k)
+	}
+	return types.ListValueMust(types.StringType, elems)
+}
+
+// refreshedIndexExpr returns the expression in state when it is equivalent to expr read from the
+// API, and expr otherwise. An empty expr is null.
+func refreshedIndexExpr(prior types.String, expr string) types.String {
+	switch {
+	case expr == "":
+		return types.StringNull()
+	case !prior.IsNull() && !prior.IsUnknown() && equivalentIndexExpr(prior.ValueString(), expr):
+		return prior
+	}
+	return types.StringValue(expr)
+}
+
+// equivalentIndexExpr reports whether two N1QL expressions have the same canonical form, that is,
+// whether they differ only in the way the query service rewrites them. A change to the case of an
+// identifier or to the grouping of operators is not equivalent.
+func equivalentIndexExpr(a, b string) bool {
+	return n1ql.CanonicalExpr(a) == n1ql.CanonicalExpr(b)
+}
@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestEquivalentIndexExpr(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"airline", "`airline`", true},
+		{"lower(name)", "lower((`name`))", true},
+		{"LOWER(name)", "lower(`name`)", true},
+		{"DISTINCT ARRAY s.day FOR s IN schedule END", "(distinct (array (`s`.`day`) for `s` in `schedule` end))", true},
+		{"type = 'airline'", "(`type` = \"airline\")", true},
+		{"HASH(meta().id)", "hash((meta().`id`))", true},
+		{"a+b*c", "(`a` + (`b` * `c`))", true},
+		{"type = 'airline'", "(`type` = \"Airline\")", false},
+		{"Name", "name", false},
+		{"(a+b)*c", "a+b*c", false},
+		{"airline", "stops", false},
+		{"stops > 0", "stops > 1", false},
+	}
+	for _, tt := range tests {
+		if got := equivalentIndexExpr(tt.a, tt.b); got != tt.want {
+			t.Errorf("equivalentIndexExpr(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestEquivalentQueryIndexDefinition(t *testing.T) {
+	index := func(where string, keys ...string) *QueryIndexModel {
+		elems := make([]attr.Value, len(keys))
+		for i, k := range keys {
+			elems[i] = types.StringValue(k)
+		}
+		return &QueryIndexModel{
+			IsPrimary:   types.BoolValue(false),
+			IndexKeys:   types.ListValueMust(types.StringType, elems),
+			Where:       types.StringValue(where),
+			PartitionBy: types.StringNull(),
+		}
+	}
+
+	tests := map[string]struct {
+		a, b *QueryIndexModel
+		want bool
+	}{
+		"respelled":          {index("type = 'airline'", "name"), index("`type` = \"airline\"", "`name`"), true},
+		"key case changed":   {index("", "name"), index("", "Name"), false},
+		"where regrouped":    {index("(a + b) * c > 0", "name"), index("a + b * c > 0", "name"), false},
+		"key regrouped":      {index("", "(a + b) * c"), index("", "a + b * c"), false},
+		"where case changed": {index("type = 'airline'", "name"), index("Type = 'airline'", "name"), false},
+	}
+	for name, tt := range tests {
+		// A definition that is not equivalent makes Update drop and recreate the index.
+		if got := equivalentQueryIndexDefinition(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: equivalentQueryIndexDefinition() = %v, want %v", name, got, tt.want)
+		}
+	}
+}